@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"orangefeed/internal/analyzer"
+	"orangefeed/internal/htmltext"
+
+	"github.com/joho/godotenv"
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+// row is one exported post and its analysis
+type row struct {
+	PostID         string   `json:"post_id"`
+	CreatedAt      string   `json:"created_at"`
+	Content        string   `json:"content"`
+	MarketImpact   string   `json:"market_impact"`
+	Confidence     float64  `json:"confidence"`
+	TradingSignal  string   `json:"trading_signal"`
+	SpecificStocks []string `json:"specific_stocks"`
+}
+
+func main() {
+	username := flag.String("user", "realDonaldTrump", "Truth Social username to export")
+	count := flag.Int("count", 200, "number of recent posts to analyze")
+	format := flag.String("format", "csv", "output format: csv or json")
+	out := flag.String("out", "", "output file (default analyses.<format>)")
+	workers := flag.Int("workers", 4, "number of posts analyzed in parallel")
+	flag.Parse()
+
+	if *format != "csv" && *format != "json" {
+		log.Fatalf("❌ Unknown format %q, use csv or json", *format)
+	}
+	if *out == "" {
+		*out = "analyses." + *format
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	truthUsername := os.Getenv("TRUTHSOCIAL_USERNAME")
+	truthPassword := os.Getenv("TRUTHSOCIAL_PASSWORD")
+	openaiKey := os.Getenv("OPENAI_API_KEY")
+	if truthUsername == "" || truthPassword == "" || openaiKey == "" {
+		log.Fatal("❌ TRUTHSOCIAL_USERNAME, TRUTHSOCIAL_PASSWORD and OPENAI_API_KEY are required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	truthClient, err := client.NewClient(ctx, truthUsername, truthPassword)
+	if err != nil {
+		log.Fatalf("❌ Failed to create Truth Social client: %v", err)
+	}
+
+	log.Printf("📄 Fetching the last %d posts from @%s...", *count, *username)
+	statuses, err := truthClient.PullStatuses(ctx, *username, true, *count)
+	if err != nil {
+		log.Fatalf("❌ Failed to fetch posts: %v", err)
+	}
+
+	cleaner := htmltext.NewCleaner()
+	// Parallel workers share the account's OpenAI rate limits
+	var limits [2]int
+	for i, key := range []string{"OPENAI_RPM", "OPENAI_TPM"} {
+		if value := os.Getenv(key); value != "" {
+			if limits[i], err = strconv.Atoi(value); err != nil || limits[i] < 0 {
+				log.Fatalf("❌ Invalid %s %q", key, value)
+			}
+		}
+	}
+	marketAnalyzer := analyzer.NewMarketAnalyzer(openaiKey, analyzer.WithRateLimit(limits[0], limits[1]))
+
+	// Keep the posts and their cleaned text side by side so every result
+	// lines up with its post
+	var posts []client.Status
+	var contents []string
+	for _, status := range statuses {
+		content := cleaner.Clean(status.Content)
+		if len(content) < 10 {
+			continue
+		}
+
+		posts = append(posts, status)
+		contents = append(contents, content)
+	}
+
+	log.Printf("🔍 Analyzing %d posts with %d workers...", len(contents), *workers)
+	results := marketAnalyzer.AnalyzeBatchConcurrent(ctx, contents, *workers)
+
+	var rows []row
+	for i, result := range results {
+		if result.Err != nil {
+			log.Printf("❌ Error analyzing post %s: %v", posts[i].ID, result.Err)
+			continue
+		}
+
+		analysis := result.Analysis
+		rows = append(rows, row{
+			PostID:         posts[i].ID,
+			CreatedAt:      fmt.Sprint(posts[i].CreatedAt),
+			Content:        contents[i],
+			MarketImpact:   analysis.MarketImpact,
+			Confidence:     analysis.Confidence,
+			TradingSignal:  analysis.TradingSignal,
+			SpecificStocks: analysis.SpecificStocks,
+		})
+	}
+
+	if err := writeRows(*out, *format, rows); err != nil {
+		log.Fatalf("❌ Failed to write %s: %v", *out, err)
+	}
+
+	log.Printf("✅ Exported %d analyses to %s", len(rows), *out)
+}
+
+func writeRows(filename, format string, rows []row) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if format == "json" {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(rows); err != nil {
+			return err
+		}
+		return file.Close()
+	}
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"post_id", "created_at", "content", "market_impact", "confidence", "trading_signal", "specific_stocks"})
+	for _, r := range rows {
+		writer.Write([]string{
+			r.PostID,
+			r.CreatedAt,
+			r.Content,
+			r.MarketImpact,
+			strconv.FormatFloat(r.Confidence, 'f', 2, 64),
+			r.TradingSignal,
+			strings.Join(r.SpecificStocks, ";"),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// authorTickers are tickers of companies tied to an author, such as DJT for
+// realDonaldTrump, which are added to the analysis of posts mentioning any
+// of Keywords. Without keywords they're added to every post.
+type authorTickers struct {
+	Tickers  []string `json:"tickers"`
+	Keywords []string `json:"keywords"`
+
+	patterns []*regexp.Regexp
+}
+
+// loadAuthorTickers reads a JSON file mapping usernames to their tickers and
+// returns the entry for username, or nil when it has none
+func loadAuthorTickers(filename, username string) (*authorTickers, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read author tickers file: %w", err)
+	}
+
+	var byAuthor map[string]*authorTickers
+	if err := json.Unmarshal(data, &byAuthor); err != nil {
+		return nil, fmt.Errorf("failed to parse author tickers file: %w", err)
+	}
+
+	for name, entry := range byAuthor {
+		if !strings.EqualFold(name, username) || entry == nil || len(entry.Tickers) == 0 {
+			continue
+		}
+		for _, keyword := range entry.Keywords {
+			keyword = strings.TrimSpace(keyword)
+			if keyword == "" {
+				return nil, fmt.Errorf("author tickers for @%s have an empty keyword", name)
+			}
+			entry.patterns = append(entry.patterns, regexp.MustCompile(`(?i)(^|\W)`+regexp.QuoteMeta(keyword)+`(\W|$)`))
+		}
+		return entry, nil
+	}
+
+	return nil, nil
+}
+
+// mentionedIn reports whether content names the author's company
+func (a *authorTickers) mentionedIn(content string) bool {
+	if len(a.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range a.patterns {
+		if pattern.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeAuthorTickers(t *testing.T, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "author_tickers.json")
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadAuthorTickers(t *testing.T) {
+	filename := writeAuthorTickers(t, `{
+		"RealDonaldTrump": {"tickers": ["DJT"], "keywords": ["Truth Social", "TMTG"]},
+		"elonmusk": {"tickers": ["TSLA"]}
+	}`)
+
+	entry, err := loadAuthorTickers(filename, "realDonaldTrump")
+	if err != nil || entry == nil {
+		t.Fatalf("loadAuthorTickers = %v, %v; want the entry matched case-insensitively", entry, err)
+	}
+	for content, want := range map[string]bool{
+		"Everyone is joining TRUTH SOCIAL!": true,
+		"Great numbers at TMTG.":            true,
+		"Tariffs on all foreign cars":       false,
+		"TruthSocialist nonsense":           false, // Keywords match whole words
+	} {
+		if got := entry.mentionedIn(content); got != want {
+			t.Errorf("mentionedIn(%q) = %t, want %t", content, got, want)
+		}
+	}
+
+	entry, err = loadAuthorTickers(filename, "elonmusk")
+	if err != nil || entry == nil || !entry.mentionedIn("anything") {
+		t.Errorf("entry without keywords should apply to every post: %v, %v", entry, err)
+	}
+
+	if entry, err := loadAuthorTickers(filename, "someoneElse"); entry != nil || err != nil {
+		t.Errorf("unknown author = %v, %v; want nil", entry, err)
+	}
+
+	if _, err := loadAuthorTickers(writeAuthorTickers(t, `{"x": {"tickers": ["A"], "keywords": [" "]}}`), "x"); err == nil {
+		t.Error("want an error for an empty keyword")
+	}
+}
+
+func TestAuthorTickersMergeIntoAnalysis(t *testing.T) {
+	bot, _ := newTestBot(t)
+	var err error
+	bot.ownTickers, err = loadAuthorTickers(writeAuthorTickers(t, `{
+		"realDonaldTrump": {"tickers": ["DJT", "$djt", "GM"], "keywords": ["Truth Social"]}
+	}`), bot.targetUsername)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis, _, err := bot.analyzePost(context.Background(), "Truth Social is doing great, and so are our car makers!", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"GM", "F", "DJT"}; !slices.Equal(analysis.SpecificStocks, want) {
+		t.Errorf("SpecificStocks = %q, want %q", analysis.SpecificStocks, want)
+	}
+
+	analysis, _, err = bot.analyzePost(context.Background(), "Tariffs on all foreign cars start next week!", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"GM", "F"}; !slices.Equal(analysis.SpecificStocks, want) {
+		t.Errorf("post not naming the company: SpecificStocks = %q, want %q", analysis.SpecificStocks, want)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"orangefeed/internal/backtest"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleBacktest compares a stored analysis with the ticker's move over the
+// analysis' time horizon: /backtest <ticker> <postID>
+func (b *OrangeFeedBot) handleBacktest(msg *tgbotapi.Message) {
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) != 2 {
+		b.sendMessageTo(msg.Chat.ID, "Usage: /backtest <ticker> <postID>")
+		return
+	}
+	ticker, postID := strings.TrimPrefix(args[0], "$"), args[1]
+
+	// Post timestamps are only kept with the stored analyses
+	if b.db == nil {
+		b.sendMessageTo(msg.Chat.ID, b.outputStyle.mark("⚠️", "")+"/backtest needs DATABASE_PATH to look up past posts.")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.fetchTimeout)
+	defer cancel()
+
+	rec, err := b.db.Analysis(ctx, postID)
+	if err != nil {
+		log.Printf("❌ Error loading analysis of post %s: %v", postID, err)
+		b.sendMessageTo(msg.Chat.ID, fmt.Sprintf("%sError loading post %s: %v", b.outputStyle.mark("⚠️", ""), postID, err))
+		return
+	}
+	if rec == nil {
+		b.sendMessageTo(msg.Chat.ID, fmt.Sprintf("No analysis stored for post %s.", postID))
+		return
+	}
+
+	postedAt, err := time.Parse(time.RFC3339, rec.CreatedAt)
+	if err != nil {
+		b.sendMessageTo(msg.Chat.ID, fmt.Sprintf("%sPost %s has no usable timestamp.", b.outputStyle.mark("⚠️", ""), postID))
+		return
+	}
+
+	result, err := backtest.Run(ctx, b.prices, ticker, postedAt, rec.Analysis)
+	if err != nil {
+		log.Printf("❌ Error backtesting %s on post %s: %v", ticker, postID, err)
+		b.sendMessageTo(msg.Chat.ID, fmt.Sprintf("%sError fetching prices for %s: %v", b.outputStyle.mark("⚠️", ""), strings.ToUpper(ticker), err))
+		return
+	}
+
+	verdict := b.outputStyle.mark("❌", "") + "Miss"
+	if result.Hit() {
+		verdict = b.outputStyle.mark("✅", "") + "Hit"
+	}
+
+	b.sendMessageTo(msg.Chat.ID, fmt.Sprintf("%s*Backtest of post %s*\nPredicted: %s (%s)\nRealized: %s\n%s\n\n%s",
+		b.outputStyle.mark("📊", ""),
+		b.escapeMarkdown(postID),
+		strings.ToUpper(result.Predicted),
+		b.escapeMarkdown(rec.Analysis.TimeHorizon),
+		strings.ToUpper(result.Realized),
+		b.escapeMarkdown(result.String()),
+		verdict))
+}
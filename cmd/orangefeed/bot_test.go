@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"orangefeed/internal/analyzer"
+	"orangefeed/internal/htmltext"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+	"github.com/sashabaranov/go-openai"
+)
+
+// fakeMessage is a message the fake messenger was asked to deliver
+type fakeMessage struct {
+	ChatID int64
+	Text   string
+	Label  string // Button label, empty for plain messages
+	Data   string // Button data
+	Opts   sendOptions
+}
+
+// fakeMessenger records messages instead of sending them. When fail is set,
+// any message it returns an error for is not recorded.
+type fakeMessenger struct {
+	sent []fakeMessage
+	fail func(chatID int64) error
+}
+
+func (f *fakeMessenger) deliver(msg fakeMessage) error {
+	if f.fail != nil {
+		if err := f.fail(msg.ChatID); err != nil {
+			return err
+		}
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func (f *fakeMessenger) Send(chatID int64, text string) error {
+	return f.deliver(fakeMessage{ChatID: chatID, Text: text})
+}
+
+func (f *fakeMessenger) SendPhoto(chatID int64, photo []byte, caption string) error {
+	return f.deliver(fakeMessage{ChatID: chatID, Text: caption})
+}
+
+func (f *fakeMessenger) SendWithButton(chatID int64, text, label, data string, opts sendOptions) error {
+	return f.deliver(fakeMessage{ChatID: chatID, Text: text, Label: label, Data: data, Opts: opts})
+}
+
+func (f *fakeMessenger) Reply(chatID int64, replyTo int, text string) error {
+	return f.deliver(fakeMessage{ChatID: chatID, Text: text})
+}
+
+// fakeCompleter answers every completion with reply and counts the calls
+type fakeCompleter struct {
+	reply string
+	calls int
+}
+
+func (f *fakeCompleter) CreateChatCompletion(ctx context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	f.calls++
+	return openai.ChatCompletionResponse{
+		Model:   request.Model,
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: f.reply}}},
+	}, nil
+}
+
+// bearishReply is a model response for a post about tariffs on cars
+const bearishReply = `{"market_impact": "bearish", "confidence": 0.8, "summary": "Car tariffs",
+	"affected_sectors": ["Automotive"], "specific_stocks": ["GM", "F"], "trading_signal": "sell",
+	"time_horizon": "short-term", "risk_level": "high"}`
+
+// newTestBot returns a bot that sends to a fake messenger and keeps its
+// state in memory only
+func newTestBot(t *testing.T) (*OrangeFeedBot, *fakeMessenger) {
+	t.Helper()
+
+	msgr := &fakeMessenger{}
+	bot := &OrangeFeedBot{
+		messenger:        msgr,
+		analyzer:         analyzer.NewMarketAnalyzerWithClient(&fakeCompleter{reply: bearishReply}),
+		cleaner:          htmltext.NewCleaner(),
+		chatID:           100,
+		targetUsername:   "realDonaldTrump",
+		stateStore:       discardStateStore{},
+		state:            newBotState(),
+		recent:           newAnalysisCache(),
+		backfillCount:    5,
+		minContentLength: 20,
+		displayZone:      time.UTC,
+		breaker:          newCircuitBreaker(3, time.Minute),
+	}
+	bot.notifiers = []namedNotifier{{name: "telegram", Notifier: telegramNotifier{b: bot}}}
+	return bot, msgr
+}
+
+// statusesFrom decodes statuses the way the API returns them
+func statusesFrom(t *testing.T, data string) []client.Status {
+	t.Helper()
+
+	var statuses []client.Status
+	if err := json.Unmarshal([]byte(data), &statuses); err != nil {
+		t.Fatalf("decoding statuses: %v", err)
+	}
+	return statuses
+}
@@ -0,0 +1,59 @@
+package main
+
+import "time"
+
+// maxBreakerCooldown caps the exponential back-off of the circuit breaker
+const maxBreakerCooldown = 6 * time.Hour
+
+// circuitBreaker pauses monitoring after repeated fetch failures. Each time
+// it trips the cool-down doubles; the first check after a cool-down acts as
+// the probe that either closes it again or re-trips it.
+type circuitBreaker struct {
+	threshold    int
+	baseCooldown time.Duration
+
+	failures  int
+	cooldown  time.Duration
+	openUntil time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, baseCooldown: cooldown}
+}
+
+// allow reports whether a check may run now
+func (cb *circuitBreaker) allow(now time.Time) bool {
+	return !now.Before(cb.openUntil)
+}
+
+// tripped reports whether the breaker has paused monitoring
+func (cb *circuitBreaker) tripped() bool {
+	return cb.cooldown > 0
+}
+
+// recordFailure counts a failed fetch and opens the breaker once the
+// threshold is reached. It returns true when the breaker (re-)opened.
+func (cb *circuitBreaker) recordFailure(now time.Time) bool {
+	cb.failures++
+	if cb.threshold <= 0 || cb.failures < cb.threshold {
+		return false
+	}
+
+	if cb.cooldown == 0 {
+		cb.cooldown = cb.baseCooldown
+	} else {
+		cb.cooldown = min(cb.cooldown*2, maxBreakerCooldown)
+	}
+	cb.openUntil = now.Add(cb.cooldown)
+
+	return true
+}
+
+// recordSuccess closes the breaker, returning whether it had been tripped
+func (cb *circuitBreaker) recordSuccess() bool {
+	wasTripped := cb.tripped()
+	cb.failures = 0
+	cb.cooldown = 0
+	cb.openUntil = time.Time{}
+	return wasTripped
+}
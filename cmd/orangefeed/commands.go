@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// command is a Telegram bot command
+type command struct {
+	name        string
+	description string
+	handler     func(b *OrangeFeedBot, msg *tgbotapi.Message)
+	public      bool     // Anyone may use it, not only authorized users
+	aliases     []string // Other names it answers to, left out of /help
+}
+
+func (b *OrangeFeedBot) commands() []command {
+	return []command{
+		{
+			name:        "help",
+			description: "List the commands you can use",
+			handler:     (*OrangeFeedBot).handleHelp,
+			public:      true,
+			aliases:     []string{"start"},
+		},
+		{
+			name:        "backtest",
+			description: "Compare a past analysis with the ticker's price move",
+			handler:     (*OrangeFeedBot).handleBacktest,
+		},
+		{
+			name:        "deadletters",
+			description: "List the posts whose analysis was given up on",
+			handler:     (*OrangeFeedBot).handleDeadLetters,
+		},
+		{
+			name:        "pause",
+			description: "Stop sending alerts until /resume",
+			handler:     (*OrangeFeedBot).handlePause,
+		},
+		{
+			name:        "resume",
+			description: "Start sending alerts again",
+			handler:     (*OrangeFeedBot).handleResume,
+		},
+		{
+			name:        "subscribe",
+			description: "Receive alerts in this chat",
+			handler:     (*OrangeFeedBot).handleSubscribe,
+			public:      true,
+		},
+		{
+			name:        "unsubscribe",
+			description: "Stop receiving alerts in this chat",
+			handler:     (*OrangeFeedBot).handleUnsubscribe,
+			public:      true,
+		},
+	}
+}
+
+// listenForCommands dispatches Telegram commands and button taps until the
+// update channel closes
+func (b *OrangeFeedBot) listenForCommands() {
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	for update := range b.telegramBot.GetUpdatesChan(u) {
+		if update.CallbackQuery != nil {
+			b.handleCallback(update.CallbackQuery)
+			continue
+		}
+		if update.Message == nil || !update.Message.IsCommand() {
+			continue
+		}
+		b.handleCommand(update.Message)
+	}
+}
+
+func (b *OrangeFeedBot) handleCommand(msg *tgbotapi.Message) {
+	for _, cmd := range b.commands() {
+		if cmd.name != msg.Command() && !slices.Contains(cmd.aliases, msg.Command()) {
+			continue
+		}
+
+		if !cmd.public && !b.isAuthorized(msg) {
+			var userID int64
+			if msg.From != nil {
+				userID = msg.From.ID
+			}
+			log.Printf("⚠️ Rejected /%s from unauthorized user %d in chat %d", msg.Command(), userID, msg.Chat.ID)
+			b.sendMessageTo(msg.Chat.ID, b.outputStyle.mark("🚫", "")+"Sorry, you're not authorized to use this bot's commands.")
+			return
+		}
+
+		log.Printf("💬 Handling /%s", cmd.name)
+		cmd.handler(b, msg)
+		return
+	}
+}
+
+// handleHelp lists the commands from the registry, leaving out the ones the
+// sender isn't allowed to use
+func (b *OrangeFeedBot) handleHelp(msg *tgbotapi.Message) {
+	authorized := b.isAuthorized(msg)
+
+	lines := []string{b.outputStyle.mark("🤖", "") + "*OrangeFeed commands*"}
+	for _, cmd := range b.commands() {
+		if !cmd.public && !authorized {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("/%s - %s", cmd.name, b.escapeMarkdown(cmd.description)))
+	}
+
+	b.sendMessageTo(msg.Chat.ID, strings.Join(lines, "\n"))
+}
+
+func (b *OrangeFeedBot) isAuthorized(msg *tgbotapi.Message) bool {
+	return b.isAuthorizedUser(msg.Chat.ID, msg.From)
+}
+
+// isAuthorizedUser checks the sender against TELEGRAM_ADMIN_IDS. Without an
+// allowlist, anyone in the broadcast chat may issue commands.
+func (b *OrangeFeedBot) isAuthorizedUser(chatID int64, from *tgbotapi.User) bool {
+	if len(b.adminIDs) == 0 {
+		return chatID == b.chatID
+	}
+
+	return from != nil && b.adminIDs[from.ID]
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// deadLetterCapacity is how many given-up posts are kept, of which
+// /deadletters lists the last deadLetterListLimit
+const (
+	deadLetterCapacity  = 50
+	deadLetterListLimit = 10
+)
+
+// deadLetterErrorLength caps the error shown for a dead letter; parse errors
+// include the whole model response
+const deadLetterErrorLength = 200
+
+// deadLetter is a post whose analysis failed on every retry
+type deadLetter struct {
+	PostID    string    `json:"post_id"`
+	URL       string    `json:"url"`
+	Failures  int       `json:"failures"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// addDeadLetter records a post that is no longer retried, and reports it to
+// the primary chat when enabled. The caller must hold checkMu.
+func (b *OrangeFeedBot) addDeadLetter(pending pendingAnalysis, err error) {
+	letter := deadLetter{
+		PostID:    pending.Status.ID,
+		URL:       pending.Status.URL,
+		Failures:  pending.Attempts + 1, // The first analysis failed too
+		LastError: err.Error(),
+		FailedAt:  time.Now(),
+	}
+
+	b.stateMu.Lock()
+	b.state.DeadLetters = append(b.state.DeadLetters, letter)
+	if extra := len(b.state.DeadLetters) - deadLetterCapacity; extra > 0 {
+		b.state.DeadLetters = b.state.DeadLetters[extra:]
+	}
+	b.stateMu.Unlock()
+
+	if b.deadLetterAlerts {
+		b.sendMessage(fmt.Sprintf("%s*Analysis given up* after %d failures: %s\n\n[View](%s)",
+			b.outputStyle.mark("🗑", ""),
+			letter.Failures,
+			b.escapeMarkdown(truncateQuote(letter.LastError, deadLetterErrorLength)),
+			letter.URL))
+	}
+}
+
+// handleDeadLetters lists the most recent posts whose analysis was given up
+// on, newest first
+func (b *OrangeFeedBot) handleDeadLetters(msg *tgbotapi.Message) {
+	b.stateMu.Lock()
+	letters := append([]deadLetter(nil), b.state.DeadLetters...)
+	b.stateMu.Unlock()
+
+	if len(letters) == 0 {
+		b.sendMessageTo(msg.Chat.ID, b.outputStyle.mark("✅", "")+"No posts have failed analysis.")
+		return
+	}
+
+	lines := []string{fmt.Sprintf("%s*%d posts failed analysis*", b.outputStyle.mark("🗑", ""), len(letters))}
+	for i := len(letters) - 1; i >= 0 && len(lines) <= deadLetterListLimit; i-- {
+		letter := letters[i]
+		lines = append(lines, fmt.Sprintf("• %s - %d failures, last %s: %s [View](%s)",
+			letter.PostID,
+			letter.Failures,
+			b.formatDisplayTime(letter.FailedAt),
+			b.escapeMarkdown(truncateQuote(letter.LastError, deadLetterErrorLength)),
+			letter.URL))
+	}
+
+	b.sendMessageTo(msg.Chat.ID, strings.Join(lines, "\n\n"))
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"orangefeed/internal/analyzer"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// detailsPrefix marks the callback data of the "Details" button; the rest
+// is the post ID
+const detailsPrefix = "details:"
+
+// recentAnalysesCapacity is how many analyses are kept in memory for the
+// "Details" button when no database is configured
+const recentAnalysesCapacity = 100
+
+// analysisCache keeps the most recent analyses by post ID. It is shared by
+// the monitoring loop and the update listener.
+type analysisCache struct {
+	mu       sync.Mutex
+	order    []string
+	analyses map[string]*analyzer.Analysis
+}
+
+func newAnalysisCache() *analysisCache {
+	return &analysisCache{analyses: make(map[string]*analyzer.Analysis)}
+}
+
+func (c *analysisCache) add(postID string, analysis *analyzer.Analysis) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.analyses[postID]; !ok {
+		c.order = append(c.order, postID)
+	}
+	c.analyses[postID] = analysis
+
+	if len(c.order) > recentAnalysesCapacity {
+		delete(c.analyses, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *analysisCache) get(postID string) *analyzer.Analysis {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.analyses[postID]
+}
+
+// lookupAnalysis finds a sent analysis in memory, then in the database
+func (b *OrangeFeedBot) lookupAnalysis(ctx context.Context, postID string) (*analyzer.Analysis, error) {
+	if analysis := b.recent.get(postID); analysis != nil {
+		return analysis, nil
+	}
+	if b.db == nil {
+		return nil, nil
+	}
+
+	rec, err := b.db.Analysis(ctx, postID)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	return rec.Analysis, nil
+}
+
+// handleCallback answers a tap on an analysis' "Details" button with the
+// full key points and insights, replying to the analysis message
+func (b *OrangeFeedBot) handleCallback(query *tgbotapi.CallbackQuery) {
+	postID, ok := strings.CutPrefix(query.Data, detailsPrefix)
+	if !ok || query.Message == nil {
+		return
+	}
+
+	if !b.isAuthorizedUser(query.Message.Chat.ID, query.From) {
+		b.answerCallback(query, b.outputStyle.mark("🚫", "")+"Not authorized")
+		return
+	}
+	b.answerCallback(query, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.lookupTimeout)
+	defer cancel()
+
+	analysis, err := b.lookupAnalysis(ctx, postID)
+	if err != nil {
+		log.Printf("❌ Error loading analysis for post %s: %v", postID, err)
+	}
+
+	text := b.formatDetails(analysis)
+	if analysis == nil {
+		text = b.outputStyle.mark("🤷", "") + "The analysis for this post is no longer available."
+	}
+
+	if err := b.messenger.Reply(query.Message.Chat.ID, query.Message.MessageID, text); err != nil {
+		log.Printf("❌ Error sending details: %v", err)
+	}
+}
+
+// answerCallback acknowledges a button tap so Telegram stops the loading
+// spinner, optionally showing a short notice
+func (b *OrangeFeedBot) answerCallback(query *tgbotapi.CallbackQuery, text string) {
+	if _, err := b.telegramBot.Request(tgbotapi.NewCallback(query.ID, text)); err != nil {
+		log.Printf("⚠️ Error answering callback: %v", err)
+	}
+}
+
+// formatDetails expands the parts of an analysis the alert leaves out
+func (b *OrangeFeedBot) formatDetails(analysis *analyzer.Analysis) string {
+	if analysis == nil {
+		return ""
+	}
+
+	style := b.outputStyle
+
+	var sb strings.Builder
+	sb.WriteString(style.mark("🔎", "") + "*Details*\n")
+
+	if len(analysis.KeyPoints) > 0 {
+		sb.WriteString("\n" + style.mark("🔑", "") + "*Key points*\n")
+		for _, point := range analysis.KeyPoints {
+			sb.WriteString("• " + b.escapeMarkdown(point) + "\n")
+		}
+	}
+
+	if !b.sentimentOnly && len(analysis.ActionableInsights) > 0 {
+		sb.WriteString("\n" + style.mark("⚡", "") + "*Insights*\n")
+		for _, insight := range analysis.ActionableInsights {
+			sb.WriteString("• " + b.escapeMarkdown(insight) + "\n")
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%s%s", style.mark("🏭", "SECTORS"), b.escapeMarkdown(formatList(analysis.AffectedSectors, len(analysis.AffectedSectors)))))
+	if !b.sentimentOnly {
+		sb.WriteString(fmt.Sprintf("\n%s%s | %s magnitude",
+			style.mark("📈", "STOCKS"),
+			b.escapeMarkdown(formatList(analysis.SpecificStocks, len(analysis.SpecificStocks))),
+			b.escapeMarkdown(analysis.ExpectedMagnitude)))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+// engagementSnapshot is a post's like and reblog counts when it was last fetched
+type engagementSnapshot struct {
+	Likes   int       `json:"likes"`
+	Reblogs int       `json:"reblogs"`
+	SeenAt  time.Time `json:"seen_at"`
+}
+
+// engagementDelta is how much a post's engagement grew over a period
+type engagementDelta struct {
+	Likes   int
+	Reblogs int
+	Period  time.Duration
+}
+
+// engagementSince returns how much the post gained since the previous check,
+// or since it was published when it hasn't been seen before
+func (b *OrangeFeedBot) engagementSince(status client.Status, now time.Time) engagementDelta {
+	previous, ok := b.state.Engagement[status.ID]
+	if !ok {
+		previous = engagementSnapshot{SeenAt: publishedAt(status)}
+	}
+
+	delta := engagementDelta{
+		Likes:   status.FavouritesCount - previous.Likes,
+		Reblogs: status.ReblogsCount - previous.Reblogs,
+		Period:  now.Sub(previous.SeenAt),
+	}
+	if previous.SeenAt.IsZero() || delta.Period < 0 {
+		delta.Period = 0
+	}
+	delta.Likes = max(delta.Likes, 0)
+	delta.Reblogs = max(delta.Reblogs, 0)
+	return delta
+}
+
+// snapshotEngagement remembers the counts of the fetched posts for the next
+// check, forgetting posts that have dropped off the timeline
+func (b *OrangeFeedBot) snapshotEngagement(statuses []client.Status, now time.Time) {
+	snapshots := make(map[string]engagementSnapshot, len(statuses))
+	for _, status := range statuses {
+		snapshots[status.ID] = engagementSnapshot{
+			Likes:   status.FavouritesCount,
+			Reblogs: status.ReblogsCount,
+			SeenAt:  now,
+		}
+	}
+	b.state.Engagement = snapshots
+}
+
+// String renders the delta for Telegram, e.g. "+5k likes, +300 reblogs in 15m"
+func (d engagementDelta) String() string {
+	return fmt.Sprintf("+%s likes, +%s reblogs in %s", compactCount(d.Likes), compactCount(d.Reblogs), formatPeriod(d.Period))
+}
+
+// compactCount shortens large counts to thousands or millions
+func compactCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// formatPeriod renders a duration in its largest sensible unit
+func formatPeriod(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
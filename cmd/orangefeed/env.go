@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envInt reads an integer environment variable, returning def when it is unset
+func envInt(key string, def int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return n, nil
+}
+
+// envList reads a comma-separated environment variable, dropping empty entries
+func envList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envFloat reads a float environment variable, returning def when it is unset
+func envFloat(key string, def float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return f, nil
+}
+
+// envIDSet reads a comma-separated list of numeric IDs
+func envIDSet(key string) (map[int64]bool, error) {
+	ids := make(map[int64]bool)
+	for _, item := range envList(key) {
+		id, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", key, item, err)
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+// envBool reads a boolean environment variable, returning def when it is unset
+func envBool(key string, def bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return v, nil
+}
+
+// envDuration reads a duration environment variable such as "90s" or "2m",
+// returning def when it is unset. Zero is allowed, and disables the features
+// that take it so.
+func envDuration(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid %s: must not be negative", key)
+	}
+
+	return d, nil
+}
+
+// envTimeout is envDuration for timeouts, which must be positive
+func envTimeout(key string, def time.Duration) (time.Duration, error) {
+	d, err := envDuration(key, def)
+	if err == nil && d == 0 {
+		err = fmt.Errorf("invalid %s: must be positive", key)
+	}
+	return d, err
+}
+
+// validateRequiredEnv checks the required variables before anything is
+// created, reporting every missing or malformed one at once. Replays don't
+// contact Telegram or Truth Social, so their credentials are optional then.
+func validateRequiredEnv(replaying bool) error {
+	var problems []error
+	if !replaying {
+		for _, key := range []string{"TELEGRAM_BOT_TOKEN", "TELEGRAM_CHAT_ID", "TRUTHSOCIAL_USERNAME", "TRUTHSOCIAL_PASSWORD"} {
+			if os.Getenv(key) == "" {
+				problems = append(problems, fmt.Errorf("%s is required", key))
+			}
+		}
+	}
+
+	if chatID := os.Getenv("TELEGRAM_CHAT_ID"); chatID != "" {
+		if _, err := strconv.ParseInt(chatID, 10, 64); err != nil {
+			problems = append(problems, fmt.Errorf("TELEGRAM_CHAT_ID must be a numeric chat ID, got %q", chatID))
+		}
+	}
+
+	// Self-hosted endpoints don't need a key, Azure and OpenAI do
+	baseURL := os.Getenv("OPENAI_BASE_URL")
+	azure := os.Getenv("AZURE_OPENAI_DEPLOYMENT") != ""
+	if os.Getenv("OPENAI_API_KEY") == "" && (baseURL == "" || azure) {
+		problems = append(problems, fmt.Errorf("OPENAI_API_KEY is required"))
+	}
+	if azure && baseURL == "" {
+		problems = append(problems, fmt.Errorf("OPENAI_BASE_URL is required when AZURE_OPENAI_DEPLOYMENT is set"))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(problems...))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", time.Minute, false}, // Unset uses the default
+		{"90s", 90 * time.Second, false},
+		{"0", 0, false},
+		{"0s", 0, false},
+		{"-1h", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("TEST_DURATION", tt.value)
+		got, err := envDuration("TEST_DURATION", time.Minute)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("envDuration(%q) = %s, %v; want %s, error %t", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestEnvTimeoutRejectsZero(t *testing.T) {
+	t.Setenv("TEST_TIMEOUT", "0")
+	if _, err := envTimeout("TEST_TIMEOUT", time.Minute); err == nil {
+		t.Error("want an error for a zero timeout")
+	}
+
+	t.Setenv("TEST_TIMEOUT", "")
+	if got, err := envTimeout("TEST_TIMEOUT", time.Minute); err != nil || got != time.Minute {
+		t.Errorf("unset timeout = %s, %v; want the default", got, err)
+	}
+}
+
+func TestValidateRequiredEnv(t *testing.T) {
+	complete := map[string]string{
+		"TELEGRAM_BOT_TOKEN":   "123:abc",
+		"TELEGRAM_CHAT_ID":     "-1001234",
+		"TRUTHSOCIAL_USERNAME": "user",
+		"TRUTHSOCIAL_PASSWORD": "pass",
+		"OPENAI_API_KEY":       "sk-test",
+	}
+
+	tests := []struct {
+		name      string
+		override  map[string]string
+		replaying bool
+		want      []string // Problems the error must list
+	}{
+		{name: "complete"},
+		{
+			name:     "nothing set",
+			override: map[string]string{"TELEGRAM_BOT_TOKEN": "", "TELEGRAM_CHAT_ID": "", "TRUTHSOCIAL_USERNAME": "", "TRUTHSOCIAL_PASSWORD": "", "OPENAI_API_KEY": ""},
+			want:     []string{"TELEGRAM_BOT_TOKEN", "TELEGRAM_CHAT_ID", "TRUTHSOCIAL_USERNAME", "TRUTHSOCIAL_PASSWORD", "OPENAI_API_KEY"},
+		},
+		{
+			name:     "bad chat ID and missing password",
+			override: map[string]string{"TELEGRAM_CHAT_ID": "@mychannel", "TRUTHSOCIAL_PASSWORD": ""},
+			want:     []string{"numeric chat ID", "TRUTHSOCIAL_PASSWORD"},
+		},
+		{
+			name:     "self-hosted endpoint without a key",
+			override: map[string]string{"OPENAI_API_KEY": "", "OPENAI_BASE_URL": "http://localhost:11434/v1"},
+		},
+		{
+			name:     "azure without an endpoint",
+			override: map[string]string{"AZURE_OPENAI_DEPLOYMENT": "gpt-4o"},
+			want:     []string{"OPENAI_BASE_URL"},
+		},
+		{
+			name:      "replaying needs no credentials",
+			override:  map[string]string{"TELEGRAM_BOT_TOKEN": "", "TELEGRAM_CHAT_ID": "", "TRUTHSOCIAL_USERNAME": "", "TRUTHSOCIAL_PASSWORD": ""},
+			replaying: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"OPENAI_BASE_URL", "AZURE_OPENAI_DEPLOYMENT"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range complete {
+				t.Setenv(key, value)
+			}
+			for key, value := range tt.override {
+				t.Setenv(key, value)
+			}
+
+			err := validateRequiredEnv(tt.replaying)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("want an error listing %q", tt.want)
+			}
+			for _, problem := range tt.want {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("error doesn't mention %s:\n%v", problem, err)
+				}
+			}
+			if got := strings.Count(err.Error(), "\n"); got != len(tt.want) {
+				t.Errorf("error lists %d problems, want %d:\n%v", got, len(tt.want), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"orangefeed/internal/analyzer"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+// outputStyle selects how alerts are decorated
+type outputStyle string
+
+const (
+	styleEmoji outputStyle = "emoji"
+	stylePlain outputStyle = "plain" // Text labels instead of emoji, for channels that forbid them
+)
+
+// mark returns the emoji in the emoji style and the text label in the plain
+// style, followed by a space. An empty label leaves plain lines unmarked.
+func (s outputStyle) mark(emoji, label string) string {
+	if s == stylePlain {
+		if label == "" {
+			return ""
+		}
+		return label + ": "
+	}
+	return emoji + " "
+}
+
+// alertExtras are optional parts of an alert, left out when nil or zero
+type alertExtras struct {
+	engagement *engagementDelta
+	nextOpen   time.Time // Next market open, for posts made while it was closed
+}
+
+// formatAnalysis renders the alert for a post and its analysis
+func (b *OrangeFeedBot) formatAnalysis(status client.Status, analysis *analyzer.Analysis, style outputStyle, extras alertExtras) string {
+	content := b.cleanContent(status.Content)
+
+	label := style.mark("🚨", "") + "*NEW POST*"
+
+	body := b.escapeMarkdown(truncateQuote(content, b.maxQuoteLength))
+
+	// Trade-specific fields are left out in sentiment-only mode
+	outlook := fmt.Sprintf("%s%s | %s | %s risk\n%s%s | %s%s",
+		style.mark("📊", "SIGNAL"),
+		b.signalText(analysis, style),
+		analysis.TimeHorizon,
+		strings.ToUpper(analysis.RiskLevel),
+		style.mark("🏭", "SECTORS"),
+		formatList(analysis.AffectedSectors, 2),
+		style.mark("📈", "STOCKS"),
+		formatList(analysis.SpecificStocks, 3))
+	if b.sentimentOnly {
+		outlook = style.mark("🏭", "SECTORS") + formatList(analysis.AffectedSectors, 2)
+	}
+
+	impact := ""
+	if style == stylePlain {
+		impact = "IMPACT: "
+	}
+
+	// Create concise analysis message
+	message := fmt.Sprintf("%s | %s%s (%.0f%%)\n\n%s%s\n\n%s\n\n%s%s",
+		label,
+		impact,
+		strings.ToUpper(analysis.MarketImpact),
+		analysis.Confidence*100,
+		style.mark("📝", "POST"),
+		body,
+		outlook,
+		style.mark("💡", "SUMMARY"),
+		b.escapeMarkdown(analysis.Summary))
+
+	if move := formatMove(analysis); move != "" {
+		message += "\n" + style.mark("📏", "MOVE") + move
+	}
+
+	// Add actionable insights if available (keep it very short)
+	if !b.sentimentOnly && len(analysis.ActionableInsights) > 0 && len(analysis.ActionableInsights[0]) > 0 {
+		message += "\n" + style.mark("⚡", "INSIGHT") + b.escapeMarkdown(analysis.ActionableInsights[0])
+	}
+
+	if !extras.nextOpen.IsZero() {
+		message += fmt.Sprintf("\n%sOutside market hours, reaction delayed to next open (%s)", style.mark("🌙", "NOTE"), formatMarketTime(extras.nextOpen))
+	}
+
+	// Add minimal post metadata
+	message += fmt.Sprintf("\n\n%s[View](%s) | %s%d | %s%d",
+		style.mark("🔗", ""),
+		status.URL,
+		style.mark("👍", "Likes"),
+		status.FavouritesCount,
+		style.mark("🔄", "Reblogs"),
+		status.ReblogsCount)
+	if extras.engagement != nil {
+		message += " | " + style.mark("🔥", "Velocity") + extras.engagement.String()
+	}
+	if postedAt := publishedAt(status); !postedAt.IsZero() {
+		message += " | " + style.mark("🕒", "Posted") + b.formatDisplayTime(postedAt)
+	}
+
+	// Record which model produced the analysis, for auditing; pre-filtered
+	// posts weren't analyzed by one
+	if b.modelFooter && analysis.Model != "" {
+		message += fmt.Sprintf("\n%smodel: %s • conf: %.0f%%",
+			style.mark("🤖", ""),
+			b.escapeMarkdown(analysis.Model),
+			analysis.Confidence*100)
+	}
+
+	return message
+}
+
+// truncateQuote shortens post text shown in an alert to limit characters,
+// marking the cut. The analyzer always gets the full text. A limit of 0
+// keeps everything.
+func truncateQuote(text string, limit int) string {
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "… [...]"
+}
+
+// formatMove renders the numeric move estimate, e.g. "+1.5% AAPL", or ""
+// when the analysis has none
+func formatMove(analysis *analyzer.Analysis) string {
+	if analysis.ExpectedMovePercent == 0 {
+		return ""
+	}
+
+	sign := "±"
+	switch analysis.Direction {
+	case "up":
+		sign = "+"
+	case "down":
+		sign = "-"
+	}
+
+	target := "S&P 500"
+	if len(analysis.SpecificStocks) > 0 {
+		target = analysis.SpecificStocks[0]
+	}
+	return fmt.Sprintf("Est. move %s%.1f%% %s", sign, analysis.ExpectedMovePercent, target)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"orangefeed/internal/analyzer"
+	"orangefeed/internal/notify"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenPost is a post with the parts alerts render: text needing escapes
+// and engagement
+const goldenPost = `{
+	"id": "114300000000000001",
+	"created_at": "2025-04-09T17:30:00.000Z",
+	"url": "https://truthsocial.com/@realDonaldTrump/114300000000000001",
+	"content": "<p>TARIFFS on *all* foreign_cars start TODAY! [Details] in the article.</p>",
+	"favourites_count": 52000,
+	"reblogs_count": 11000,
+	"account": {"username": "realDonaldTrump"}
+}`
+
+func goldenAnalysis() *analyzer.Analysis {
+	return &analyzer.Analysis{
+		MarketImpact:       "bearish",
+		Confidence:         0.82,
+		Summary:            "25% tariff on imported cars hits foreign automakers",
+		KeyPoints:          []string{"Applies to all imports"},
+		AffectedSectors:    []string{"Automotive", "Retail", "Shipping"},
+		SpecificStocks:     []string{"TM", "HMC", "F", "GM"},
+		TradingSignal:      "sell",
+		TimeHorizon:        "short-term",
+		RiskLevel:          "high",
+		ActionableInsights: []string{"Watch importers' margins"},
+		Model:              "gpt-4o",
+	}
+}
+
+// checkGolden compares got with testdata/name, or rewrites it with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s doesn't match the rendered alert:\n--- got\n%s\n--- want\n%s", name, got, want)
+	}
+}
+
+func TestSendAnalysisGolden(t *testing.T) {
+	for _, style := range []outputStyle{styleEmoji, stylePlain} {
+		t.Run(string(style), func(t *testing.T) {
+			bot, msgr := newTestBot(t)
+			bot.outputStyle = style
+			bot.modelFooter = true
+
+			status := statusesFrom(t, "["+goldenPost+"]")[0]
+			bot.sendAnalysis(context.Background(), status, goldenAnalysis(), alertExtras{})
+
+			if len(msgr.sent) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(msgr.sent))
+			}
+			sent := msgr.sent[0]
+			if sent.ChatID != bot.chatID || sent.Data != detailsPrefix+status.ID {
+				t.Errorf("sent to %d with button data %q", sent.ChatID, sent.Data)
+			}
+			checkGolden(t, "alert_"+string(style)+".golden", sent.Text)
+		})
+	}
+}
+
+func TestFormatList(t *testing.T) {
+	tests := []struct {
+		items []string
+		max   int
+		want  string
+	}{
+		{nil, 3, "None"},
+		{[]string{"AAPL"}, 3, "AAPL"},
+		{[]string{"AAPL", "MSFT", "NVDA"}, 3, "AAPL, MSFT, NVDA"},
+		{[]string{"AAPL", "MSFT", "NVDA", "TSLA", "GM"}, 3, "AAPL, MSFT, NVDA +2"},
+	}
+
+	for _, tt := range tests {
+		if got := formatList(tt.items, tt.max); got != tt.want {
+			t.Errorf("formatList(%v, %d) = %q, want %q", tt.items, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestGetSignalEmoji(t *testing.T) {
+	tests := map[string]string{
+		"buy":   "🟢",
+		"SELL":  "🔴",
+		"hold":  "🟡",
+		"Watch": "👀",
+		"":      "⚪",
+	}
+
+	for signal, want := range tests {
+		if got := getSignalEmoji(signal); got != want {
+			t.Errorf("getSignalEmoji(%q) = %q, want %q", signal, got, want)
+		}
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		text, v1, v2 string
+	}{
+		{"plain text", "plain text", "plain text"},
+		{"foreign_cars *now*", "foreign\\_cars \\*now\\*", "foreign\\_cars \\*now\\*"},
+		{"[link](url) `code`", "\\[link](url) \\`code\\`", "\\[link\\]\\(url\\) \\`code\\`"},
+		{"Up 5.2% - great!", "Up 5.2% - great!", "Up 5\\.2% \\- great\\!"},
+		{"a\\b", "a\\b", "a\\\\b"},
+	}
+
+	bot, _ := newTestBot(t)
+	for _, tt := range tests {
+		if got := escapeMarkdownV1(tt.text); got != tt.v1 {
+			t.Errorf("escapeMarkdownV1(%q) = %q, want %q", tt.text, got, tt.v1)
+		}
+		if got := escapeMarkdownV2(tt.text); got != tt.v2 {
+			t.Errorf("escapeMarkdownV2(%q) = %q, want %q", tt.text, got, tt.v2)
+		}
+	}
+	if got, want := bot.escapeMarkdown("foreign_cars"), escapeMarkdownV1("foreign_cars"); got != want {
+		t.Errorf("escapeMarkdown uses the wrong mode for %s: got %q, want %q", parseMode, got, want)
+	}
+}
+
+// emojiPattern matches the pictographs the emoji style uses
+var emojiPattern = regexp.MustCompile(`[\x{2139}\x{23E9}-\x{23FA}\x{2600}-\x{27BF}\x{1F300}-\x{1FAFF}]`)
+
+func TestPlainStyleHasNoEmoji(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.outputStyle = stylePlain
+	bot.state.Subscribers = []int64{200}
+
+	analysis := &analyzer.Analysis{
+		MarketImpact:       "bearish",
+		KeyPoints:          []string{"New tariffs"},
+		ActionableInsights: []string{"Watch retailers"},
+		AffectedSectors:    []string{"Retail"},
+		SpecificStocks:     []string{"WMT"},
+		TradingSignal:      "sell",
+		ExpectedMagnitude:  "moderate",
+	}
+	bot.notify(context.Background(), notify.Message{PostID: "111", Text: "alert", Analysis: analysis})
+	bot.breaker = newCircuitBreaker(2, time.Minute)
+	bot.handleFetchFailure(context.DeadlineExceeded)
+	bot.handleFetchFailure(context.DeadlineExceeded)
+
+	texts := map[string]string{"details": bot.formatDetails(analysis)}
+	for _, m := range msgr.sent {
+		texts["button to "+m.Text] = m.Label
+		texts[m.Text] = m.Text
+	}
+	for name, text := range texts {
+		if emoji := emojiPattern.FindString(text); emoji != "" {
+			t.Errorf("%s has %q in the plain style:\n%s", name, emoji, text)
+		}
+	}
+}
+
+func TestTruncateQuote(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{"Tariffs!", 8, "Tariffs!"},         // Exactly at the limit
+		{"Tariffs!!", 8, "Tariffs!… [...]"}, // One over
+		{"Big tariffs", 4, "Big… [...]"},    // Trailing space trimmed at the cut
+		{"Très grand", 4, "Très… [...]"},    // Counts characters, not bytes
+		{"Anything at all", 0, "Anything at all"},
+	}
+
+	for _, tt := range tests {
+		if got := truncateQuote(tt.text, tt.limit); got != tt.want {
+			t.Errorf("truncateQuote(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestAlertQuotesTruncatedText(t *testing.T) {
+	bot, messenger := newTestBot(t)
+	bot.maxQuoteLength = 20
+	status := statusesFrom(t, `[{"id": "1", "created_at": "2025-04-10T12:00:00.000Z",
+		"content": "<p>Tariffs on all foreign cars start next week, BIG changes for Detroit!</p>"}]`)[0]
+
+	bot.sendAnalysis(context.Background(), status, &analyzer.Analysis{MarketImpact: "bearish"}, alertExtras{})
+
+	if len(messenger.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(messenger.sent))
+	}
+	if text := messenger.sent[0].Text; !strings.Contains(text, "Tariffs on all forei… \\[...]") || strings.Contains(text, "Detroit") {
+		t.Errorf("quote not cut at 20 characters:\n%s", text)
+	}
+}
@@ -2,16 +2,29 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"orangefeed/internal/analyzer"
+	"orangefeed/internal/audit"
+	"orangefeed/internal/backtest"
+	"orangefeed/internal/features"
+	"orangefeed/internal/htmltext"
+	"orangefeed/internal/markethours"
+	"orangefeed/internal/notify"
+	"orangefeed/internal/prefilter"
+	"orangefeed/internal/prompts"
+	"orangefeed/internal/store"
+	"orangefeed/internal/textsim"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/joho/godotenv"
@@ -20,12 +33,82 @@ import (
 )
 
 type OrangeFeedBot struct {
-	telegramBot    *tgbotapi.BotAPI
-	truthClient    *client.Client
-	analyzer       *analyzer.MarketAnalyzer
-	chatID         int64
-	targetUsername string
-	lastPostID     string
+	telegramBot      *tgbotapi.BotAPI
+	messenger        messenger
+	truthClient      *client.Client
+	analyzer         *analyzer.MarketAnalyzer
+	cleaner          *htmltext.Cleaner
+	chatID           int64
+	targetUsername   string
+	stateStore       stateStore
+	state            *botState
+	db               *store.Store
+	recent           *analysisCache
+	auditLog         *audit.Log
+	notifiers        []namedNotifier
+	profiles         *profileCache // Author lookups for the prompt; nil when disabled
+	backfillCount    int
+	maxPostsPerTick  int // New posts analyzed per check, oldest first; 0 is unlimited
+	minContentLength int
+	maxQuoteLength   int // Post text in alerts is cut to this many characters; 0 keeps all
+	contextPosts     int // Earlier posts from the same fetch given to the model as background
+	routes           []route
+	watchlist        []string
+	alertSignals     []string
+	adminIDs         map[int64]bool
+	prices           backtest.PriceProvider
+	checkMu          sync.Mutex // Held while a check runs so overlapping ticks skip; guards state not under stateMu
+	breaker          *circuitBreaker
+
+	// Guards the state that commands use (pause, subscribers and dead
+	// letters) and saving it, so commands don't wait for a running check
+	stateMu    sync.Mutex
+	stateDirty bool // Changed by a command while a check ran and not saved yet
+
+	sentimentWindow    int
+	sentimentThreshold float64
+
+	similarityThreshold float64
+	similarityHistory   int
+
+	engagementVelocity bool
+	marketHoursAware   bool
+
+	sentimentOnly bool
+	outputStyle   outputStyle
+	modelFooter   bool           // Name the model and its confidence at the end of alerts
+	displayZone   *time.Location // Times in messages are shown in this zone
+
+	lookupTimeout time.Duration // Startup account lookup
+	fetchTimeout  time.Duration // One check or command, fetch and analysis included
+	maxPostAge    time.Duration // Older posts are skipped; zero allows any age
+
+	fetchRetries    int           // Extra fetch attempts within one check
+	fetchRetryDelay time.Duration // Wait between fetch attempts
+
+	// pullStatuses fetches a user's latest posts; the client's, unless a
+	// test swaps it
+	pullStatuses func(ctx context.Context, username string, excludeReplies bool, limit int) ([]client.Status, error)
+
+	sendRetries    int           // Extra attempts at a failed alert delivery
+	sendRetryDelay time.Duration // Wait between delivery attempts
+
+	// Keyword scoring that spares the model obviously irrelevant posts; nil
+	// analyzes every post
+	relevance *prefilter.Filter
+
+	// Tickers of companies tied to the target author; nil when none are set
+	ownTickers *authorTickers
+
+	quietHours  *quietHours // Alerts are held for a digest inside this window; nil never holds
+	silentBelow int         // Alerts with a lower alertPriority don't notify
+
+	recordDir  string // Each check's statuses and decisions are written here when set
+	replayFrom string // Recorded checks to replay instead of monitoring
+
+	pauseSkips bool // While paused, don't fetch at all, so /resume catches up on missed posts
+
+	deadLetterAlerts bool // Tell the primary chat when a post's analysis is given up on
 }
 
 func main() {
@@ -51,6 +134,13 @@ func main() {
 		log.Fatal("Failed to initialize OrangeFeed bot:", err)
 	}
 
+	if bot.replayFrom != "" {
+		if err := bot.replay(bot.replayFrom); err != nil {
+			log.Fatal("Replay failed:", err)
+		}
+		return
+	}
+
 	// Start the monitoring system in a goroutine
 	go bot.Start()
 
@@ -59,84 +149,562 @@ func main() {
 	log.Printf("🔔 Received signal: %v. Shutting down gracefully...", sig)
 
 	// Send shutdown notification to Telegram
-	bot.sendMessage("🛑 *OrangeFeed Bot Shutting Down*\n\nThe bot has been stopped and is no longer monitoring for new posts.")
+	bot.sendMessage(bot.outputStyle.mark("🛑", "") + "*OrangeFeed Bot Shutting Down*\n\nThe bot has been stopped and is no longer monitoring for new posts.")
 }
 
 func NewOrangeFeedBot() (*OrangeFeedBot, error) {
+	// Replays feed recorded checks through the analysis path without
+	// touching Telegram, Truth Social or the saved state
+	replayFrom := os.Getenv("REPLAY_FROM")
+
+	if err := validateRequiredEnv(replayFrom != ""); err != nil {
+		return nil, err
+	}
+
 	// Initialize Telegram bot
-	telegramToken := os.Getenv("TELEGRAM_BOT_TOKEN")
-	if telegramToken == "" {
-		return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN is required")
+	var err error
+	var telegramBot *tgbotapi.BotAPI
+	var msgr messenger = &replayMessenger{}
+	if replayFrom == "" {
+		telegramBot, err = tgbotapi.NewBotAPI(os.Getenv("TELEGRAM_BOT_TOKEN"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create telegram bot: %w", err)
+		}
+		msgr = &telegramMessenger{bot: telegramBot}
+	}
+
+	// Get chat ID; replays may leave it out
+	var chatID int64
+	if value := os.Getenv("TELEGRAM_CHAT_ID"); value != "" || replayFrom == "" {
+		chatID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TELEGRAM_CHAT_ID: %w", err)
+		}
 	}
 
-	telegramBot, err := tgbotapi.NewBotAPI(telegramToken)
+	// Initialize Truth Social client
+	truthUsername := os.Getenv("TRUTHSOCIAL_USERNAME")
+	truthPassword := os.Getenv("TRUTHSOCIAL_PASSWORD")
+
+	authTimeout, err := envTimeout("AUTH_TIMEOUT", 60*time.Second)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create telegram bot: %w", err)
+		return nil, err
 	}
 
-	// Get chat ID
-	chatIDStr := os.Getenv("TELEGRAM_CHAT_ID")
-	if chatIDStr == "" {
-		return nil, fmt.Errorf("TELEGRAM_CHAT_ID is required")
+	lookupTimeout, err := envTimeout("LOOKUP_TIMEOUT", 60*time.Second)
+	if err != nil {
+		return nil, err
 	}
 
-	chatID, err := strconv.ParseInt(chatIDStr, 10, 64)
+	fetchTimeout, err := envTimeout("FETCH_TIMEOUT", 120*time.Second)
 	if err != nil {
-		return nil, fmt.Errorf("invalid TELEGRAM_CHAT_ID: %w", err)
+		return nil, err
 	}
 
-	// Initialize Truth Social client
-	truthUsername := os.Getenv("TRUTHSOCIAL_USERNAME")
-	truthPassword := os.Getenv("TRUTHSOCIAL_PASSWORD")
-	if truthUsername == "" || truthPassword == "" {
-		return nil, fmt.Errorf("TRUTHSOCIAL_USERNAME and TRUTHSOCIAL_PASSWORD are required")
+	fetchRetries, err := envInt("FETCH_RETRIES", 2)
+	if err != nil {
+		return nil, err
+	}
+	fetchRetryDelay, err := envDuration("FETCH_RETRY_DELAY", 5*time.Second)
+	if err != nil {
+		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	sendRetries, err := envInt("SEND_RETRIES", 2)
+	if err != nil {
+		return nil, err
+	}
+	sendRetryDelay, err := envDuration("SEND_RETRY_DELAY", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
 	defer cancel()
 
-	truthClient, err := client.NewClient(ctx, truthUsername, truthPassword)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Truth Social client: %w", err)
+	var truthClient *client.Client
+	if replayFrom == "" {
+		truthClient, err = client.NewClient(ctx, truthUsername, truthPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Truth Social client: %w", err)
+		}
 	}
 
-	// Initialize market analyzer
+	// Initialize market analyzer. Self-hosted OpenAI-compatible servers
+	// (Ollama, llama.cpp) don't check the key, so it may be left out there.
 	openaiKey := os.Getenv("OPENAI_API_KEY")
+	openaiBaseURL := os.Getenv("OPENAI_BASE_URL")
 	if openaiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY is required")
+		openaiKey = "unused"
+	}
+
+	var analyzerOpts []analyzer.Option
+	if deployment := os.Getenv("AZURE_OPENAI_DEPLOYMENT"); deployment != "" {
+		analyzerOpts = append(analyzerOpts, analyzer.WithAzure(openaiBaseURL, deployment, os.Getenv("AZURE_OPENAI_API_VERSION")))
+		log.Printf("☁️ Using Azure OpenAI deployment %s at %s", deployment, openaiBaseURL)
+	} else if openaiBaseURL != "" {
+		analyzerOpts = append(analyzerOpts, analyzer.WithBaseURL(openaiBaseURL))
+		log.Printf("🔌 Using OpenAI-compatible endpoint %s", openaiBaseURL)
+	}
+
+	if model := os.Getenv("OPENAI_MODEL"); model != "" {
+		analyzerOpts = append(analyzerOpts, analyzer.WithModel(model))
+	}
+
+	if promptFile := os.Getenv("PROMPT_FILE"); promptFile != "" {
+		template, err := prompts.LoadTemplate(promptFile)
+		if err != nil {
+			return nil, err
+		}
+		analyzerOpts = append(analyzerOpts, analyzer.WithPromptTemplate(template))
+		log.Printf("📝 Using analysis prompt from %s", promptFile)
 	}
 
-	analyzer := analyzer.NewMarketAnalyzer(openaiKey)
+	openaiTimeout, err := envTimeout("OPENAI_TIMEOUT", 45*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	analyzerOpts = append(analyzerOpts, analyzer.WithRequestTimeout(openaiTimeout))
+
+	// Checks and commands share the analyzer; cap their combined OpenAI calls
+	concurrency, err := envInt("ANALYSIS_CONCURRENCY", 1)
+	if err != nil {
+		return nil, err
+	}
+	if concurrency < 1 {
+		return nil, fmt.Errorf("invalid ANALYSIS_CONCURRENCY: must be at least 1")
+	}
+	analyzerOpts = append(analyzerOpts, analyzer.WithMaxConcurrency(concurrency))
+
+	openaiRPM, err := envInt("OPENAI_RPM", 0)
+	if err != nil {
+		return nil, err
+	}
+	openaiTPM, err := envInt("OPENAI_TPM", 0)
+	if err != nil {
+		return nil, err
+	}
+	if openaiRPM < 0 || openaiTPM < 0 {
+		return nil, fmt.Errorf("invalid OPENAI_RPM/OPENAI_TPM: must not be negative")
+	}
+	analyzerOpts = append(analyzerOpts, analyzer.WithRateLimit(openaiRPM, openaiTPM))
+
+	// Sentiment mode keeps buy/sell recommendations out of the alerts
+	var sentimentOnly bool
+	switch mode := os.Getenv("ADVICE_MODE"); mode {
+	case "", "full":
+	case "sentiment":
+		sentimentOnly = true
+		analyzerOpts = append(analyzerOpts, analyzer.WithSentimentOnly())
+		log.Println("🧘 Sentiment-only mode, trading advice is disabled")
+	default:
+		return nil, fmt.Errorf("invalid ADVICE_MODE %q: must be sentiment or full", mode)
+	}
+	if sentimentOnly && os.Getenv("PROMPT_FILE") != "" {
+		return nil, fmt.Errorf("PROMPT_FILE can't be used with ADVICE_MODE=sentiment, which needs the built-in sentiment prompt")
+	}
+
+	// Numeric move estimates are a trading signal of their own
+	estimateMove, err := envBool("ESTIMATE_MAGNITUDE", false)
+	if err != nil {
+		return nil, err
+	}
+	if estimateMove && sentimentOnly {
+		return nil, fmt.Errorf("ESTIMATE_MAGNITUDE can't be used with ADVICE_MODE=sentiment, which has no price estimates")
+	}
+	if estimateMove {
+		analyzerOpts = append(analyzerOpts, analyzer.WithMoveEstimate())
+	}
+
+	analyzer := analyzer.NewMarketAnalyzer(openaiKey, analyzerOpts...)
 
 	targetUsername := os.Getenv("TARGET_USERNAME")
 	if targetUsername == "" {
 		targetUsername = "realDonaldTrump"
 	}
 
-	return &OrangeFeedBot{
-		telegramBot:    telegramBot,
-		truthClient:    truthClient,
-		analyzer:       analyzer,
-		chatID:         chatID,
-		targetUsername: targetUsername,
-	}, nil
+	// Keep state and analyses in SQLite when configured, otherwise in a JSON file
+	var db *store.Store
+	var states stateStore
+	if dbPath := os.Getenv("DATABASE_PATH"); replayFrom != "" {
+		states = discardStateStore{}
+	} else if dbPath != "" {
+		db, err = store.Open(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		states = dbStateStore{db: db}
+		log.Printf("🗄️ Using SQLite database %s", dbPath)
+	} else {
+		statePath := os.Getenv("STATE_FILE")
+		if statePath == "" {
+			statePath = "orangefeed_state.json"
+		}
+		states = fileStateStore{path: statePath}
+	}
+
+	state, err := states.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if db != nil && state.LastPostID == "" {
+		state.LastPostID, err = db.LastPostID(ctx, targetUsername)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	backfillCount, err := envInt("BACKFILL_COUNT", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Caps OpenAI spend during a posting spree; the rest waits for later checks.
+	// Deferred posts must still be among the fetchLimit newest next time, so
+	// the default stays well below it.
+	maxPostsPerTick, err := envInt("MAX_POSTS_PER_TICK", 5)
+	if err != nil {
+		return nil, err
+	}
+	if maxPostsPerTick < 0 {
+		return nil, fmt.Errorf("invalid MAX_POSTS_PER_TICK: must not be negative")
+	}
+
+	minContentLength, err := envInt("MIN_CONTENT_LENGTH", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	maxQuoteLength, err := envInt("MAX_QUOTE_LENGTH", 280)
+	if err != nil {
+		return nil, err
+	}
+
+	contextPosts, err := envInt("CONTEXT_POSTS", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	similarityThreshold, err := envFloat("SIMILARITY_THRESHOLD", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	similarityHistory, err := envInt("SIMILARITY_HISTORY", 20)
+	if err != nil {
+		return nil, err
+	}
+
+	engagementVelocity, err := envBool("ENGAGEMENT_VELOCITY", false)
+	if err != nil {
+		return nil, err
+	}
+
+	marketHoursAware, err := envBool("MARKET_HOURS_AWARE", false)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPostAge, err := envDuration("MAX_POST_AGE", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only ask the model about posts that mention market-moving topics
+	var relevance *prefilter.Filter
+	prefilterThreshold, err := envFloat("PREFILTER_THRESHOLD", 0)
+	if err != nil {
+		return nil, err
+	}
+	if prefilterThreshold > 0 {
+		terms := prefilter.DefaultTerms
+		if keywords := envList("PREFILTER_KEYWORDS"); len(keywords) > 0 {
+			terms, err = prefilter.ParseTerms(keywords)
+			if err != nil {
+				return nil, err
+			}
+		}
+		relevance, err = prefilter.New(terms, prefilterThreshold)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Alerts below this priority are delivered without a notification sound
+	silentBelow, err := parsePriority(os.Getenv("SILENT_BELOW"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Hold alerts back at night and send them as one digest in the morning
+	var quiet *quietHours
+	if window := os.Getenv("QUIET_HOURS"); window != "" {
+		loc := time.Local
+		if tz := os.Getenv("QUIET_HOURS_TZ"); tz != "" {
+			loc, err = time.LoadLocation(tz)
+			if err != nil {
+				return nil, fmt.Errorf("invalid QUIET_HOURS_TZ: %w", err)
+			}
+		}
+		quiet, err = parseQuietHours(window, loc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// While /pause is on, posts are either passed over ("advance") or left
+	// for after /resume ("skip")
+	var pauseSkips bool
+	switch mode := os.Getenv("PAUSE_MODE"); mode {
+	case "", "advance":
+	case "skip":
+		pauseSkips = true
+	default:
+		return nil, fmt.Errorf("invalid PAUSE_MODE %q: must be advance or skip", mode)
+	}
+
+	deadLetterAlerts, err := envBool("DEAD_LETTER_ALERTS", false)
+	if err != nil {
+		return nil, err
+	}
+
+	authorContext, err := envBool("AUTHOR_CONTEXT", false)
+	if err != nil {
+		return nil, err
+	}
+
+	profileTTL, err := envDuration("AUTHOR_CACHE_TTL", 6*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	breakerThreshold, err := envInt("BREAKER_THRESHOLD", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	breakerCooldown, err := envInt("BREAKER_COOLDOWN_MINUTES", 15)
+	if err != nil {
+		return nil, err
+	}
+
+	adminIDs, err := envIDSet("TELEGRAM_ADMIN_IDS")
+	if err != nil {
+		return nil, err
+	}
+
+	sentimentWindow, err := envInt("SENTIMENT_WINDOW", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	sentimentThreshold, err := envFloat("SENTIMENT_THRESHOLD", 0.3)
+	if err != nil {
+		return nil, err
+	}
+
+	// Optional JSON-lines record of every alert, independent of the database
+	var auditLog *audit.Log
+	if auditPath := os.Getenv("AUDIT_LOG"); auditPath != "" && replayFrom == "" {
+		maxMB, err := envInt("AUDIT_LOG_MAX_MB", 10)
+		if err != nil {
+			return nil, err
+		}
+
+		auditLog, err = audit.Open(auditPath, int64(maxMB)<<20)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("🧾 Writing audit log to %s", auditPath)
+	}
+
+	// Only alert on these trading signals; empty means every signal
+	var alertSignals []string
+	for _, signal := range envList("ALERT_SIGNALS") {
+		signal = strings.ToLower(signal)
+		if !slices.Contains([]string{"buy", "sell", "hold", "watch"}, signal) {
+			return nil, fmt.Errorf("invalid ALERT_SIGNALS entry %q: must be buy, sell, hold or watch", signal)
+		}
+		alertSignals = append(alertSignals, signal)
+	}
+	if len(alertSignals) > 0 && sentimentOnly {
+		return nil, fmt.Errorf("ALERT_SIGNALS can't be used with ADVICE_MODE=sentiment, which has no signals")
+	}
+
+	// Plain style drops emoji for channels that don't allow them
+	style := styleEmoji
+	switch value := os.Getenv("OUTPUT_STYLE"); value {
+	case "", string(styleEmoji):
+	case string(stylePlain):
+		style = stylePlain
+	default:
+		return nil, fmt.Errorf("invalid OUTPUT_STYLE %q: must be emoji or plain", value)
+	}
+
+	modelFooter, err := envBool("SHOW_MODEL_FOOTER", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// A typo in the zone shouldn't keep alerts from going out
+	displayZone := time.UTC
+	if name := os.Getenv("DISPLAY_TIMEZONE"); name != "" {
+		if loc, err := time.LoadLocation(name); err != nil {
+			log.Printf("⚠️ Invalid DISPLAY_TIMEZONE %q, showing times in UTC: %v", name, err)
+		} else {
+			displayZone = loc
+		}
+	}
+
+	// truthsocial-go doesn't report a post's language, so there is nothing
+	// to filter on; say so rather than let every post through unnoticed
+	if languages := envList("LANGUAGES"); len(languages) > 0 {
+		log.Printf("⚠️ LANGUAGES=%s is ignored: the Truth Social client doesn't report post languages", strings.Join(languages, ","))
+	}
+
+	// Boilerplate removed before analysis: the built-in patterns, plus or
+	// instead of those in STRIP_PATTERNS_FILE
+	stripPatterns := htmltext.DefaultStripPatterns
+	if stripFile := os.Getenv("STRIP_PATTERNS_FILE"); stripFile != "" {
+		stripPatterns, err = loadStripPatterns(stripFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cleaner, err := htmltext.NewCleanerWithPatterns(stripPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Optional sector/ticker routing to additional chats
+	var routes []route
+	if routesFile := os.Getenv("ROUTES_FILE"); routesFile != "" {
+		routes, err = loadRoutes(routesFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("🧭 Loaded %d alert routes from %s", len(routes), routesFile)
+	}
+
+	// Companies tied to the author, like DJT for Truth Social's owner
+	var ownTickers *authorTickers
+	if tickersFile := os.Getenv("AUTHOR_TICKERS_FILE"); tickersFile != "" {
+		ownTickers, err = loadAuthorTickers(tickersFile, targetUsername)
+		if err != nil {
+			return nil, err
+		}
+		if ownTickers != nil {
+			log.Printf("🏢 Tickers tied to @%s: %s", targetUsername, strings.Join(ownTickers.Tickers, ", "))
+		}
+	}
+
+	bot := &OrangeFeedBot{
+		telegramBot:      telegramBot,
+		messenger:        msgr,
+		truthClient:      truthClient,
+		analyzer:         analyzer,
+		cleaner:          cleaner,
+		chatID:           chatID,
+		targetUsername:   targetUsername,
+		stateStore:       states,
+		state:            state,
+		db:               db,
+		prices:           backtest.StooqProvider{},
+		recent:           newAnalysisCache(),
+		auditLog:         auditLog,
+		backfillCount:    backfillCount,
+		maxPostsPerTick:  maxPostsPerTick,
+		minContentLength: minContentLength,
+		maxQuoteLength:   maxQuoteLength,
+		contextPosts:     contextPosts,
+		routes:           routes,
+		watchlist:        envList("WATCHLIST"),
+		alertSignals:     alertSignals,
+		adminIDs:         adminIDs,
+		breaker:          newCircuitBreaker(breakerThreshold, time.Duration(breakerCooldown)*time.Minute),
+
+		sentimentWindow:    sentimentWindow,
+		sentimentThreshold: sentimentThreshold,
+
+		similarityThreshold: similarityThreshold,
+		similarityHistory:   similarityHistory,
+
+		engagementVelocity: engagementVelocity,
+		marketHoursAware:   marketHoursAware,
+
+		sentimentOnly: sentimentOnly,
+		outputStyle:   style,
+		modelFooter:   modelFooter,
+		displayZone:   displayZone,
+
+		lookupTimeout: lookupTimeout,
+		fetchTimeout:  fetchTimeout,
+		maxPostAge:    maxPostAge,
+
+		fetchRetries:    fetchRetries,
+		fetchRetryDelay: fetchRetryDelay,
+
+		sendRetries:    sendRetries,
+		sendRetryDelay: sendRetryDelay,
+
+		relevance:   relevance,
+		quietHours:  quiet,
+		silentBelow: silentBelow,
+
+		ownTickers: ownTickers,
+
+		recordDir:  os.Getenv("RECORD_DIR"),
+		replayFrom: replayFrom,
+
+		pauseSkips: pauseSkips,
+
+		deadLetterAlerts: deadLetterAlerts,
+	}
+
+	if truthClient != nil {
+		bot.pullStatuses = truthClient.PullStatuses
+	}
+	if authorContext && truthClient != nil {
+		bot.profiles = newProfileCache(profileTTL, truthClient.Lookup)
+	}
+
+	// Alert destinations; the Telegram one needs the bot for routing
+	bot.notifiers, err = loadNotifiers(bot)
+	if err != nil {
+		return nil, err
+	}
+	if replayFrom != "" {
+		bot.notifiers = []namedNotifier{{name: "telegram", Notifier: telegramNotifier{b: bot}}}
+	}
+
+	return bot, nil
 }
 
 func (b *OrangeFeedBot) Start() {
 	log.Printf("🚀 Starting OrangeFeed monitoring for @%s", b.targetUsername)
 
-	// Send startup message
-	b.sendMessage(fmt.Sprintf(`🤖 *OrangeFeed Market Intelligence Bot Started!*
+	style := b.outputStyle
+	startupMessage := fmt.Sprintf(`%s*OrangeFeed Market Intelligence Bot Started!*
 
-📊 Monitoring: @%s
-🎯 Features:
+%sMonitoring: @%s
+%sFeatures:
 • Real-time Truth Social monitoring
 • Advanced AI market analysis
 • Specific stock recommendations
 • Trading signals & risk assessment
 • Sector impact analysis
 
-🔄 Bot is now active and monitoring for new posts...`, b.targetUsername))
+%sBot is now active and monitoring for new posts...`,
+		style.mark("🤖", ""), style.mark("📊", ""), b.targetUsername, style.mark("🎯", ""), style.mark("🔄", ""))
+
+	// Fail fast on bad credentials instead of at the first cron tick
+	ctx, cancel := context.WithTimeout(context.Background(), b.lookupTimeout)
+	defer cancel()
+
+	if err := b.preflight(ctx, startupMessage); err != nil {
+		log.Fatalf("❌ Preflight check failed: %v", err)
+	}
 
 	// Set up cron job for monitoring
 	c := cron.New()
@@ -152,7 +720,19 @@ func (b *OrangeFeedBot) Start() {
 	}
 
 	cronExpr := fmt.Sprintf("*/%d * * * *", interval)
-	log.Printf("⏰ Setting up monitoring every %d minutes", interval)
+	schedule := fmt.Sprintf("every %d minutes", interval)
+
+	// A raw cron expression (e.g. market hours only) takes precedence
+	if customExpr := os.Getenv("CHECK_CRON"); customExpr != "" {
+		if _, err := cron.ParseStandard(customExpr); err != nil {
+			log.Printf("⚠️ Invalid CHECK_CRON %q, falling back to interval: %v", customExpr, err)
+		} else {
+			cronExpr = customExpr
+			schedule = fmt.Sprintf("on schedule %q", customExpr)
+		}
+	}
+
+	log.Printf("⏰ Setting up monitoring %s", schedule)
 
 	c.AddFunc(cronExpr, func() {
 		log.Println("🔍 Checking for new posts...")
@@ -161,99 +741,632 @@ func (b *OrangeFeedBot) Start() {
 
 	c.Start()
 
+	// Handle Telegram commands such as /pause
+	go b.listenForCommands()
+
 	// Keep the program running
 	log.Println("✅ OrangeFeed is running. Press Ctrl+C to stop.")
 }
 
+// preflight verifies that the Truth Social session works and the Telegram
+// chat is reachable by looking up the target and sending the startup message
+func (b *OrangeFeedBot) preflight(ctx context.Context, startupMessage string) error {
+	account, err := b.truthClient.Lookup(ctx, b.targetUsername)
+	if err != nil {
+		return fmt.Errorf("looking up @%s on Truth Social failed (check TRUTHSOCIAL_USERNAME/TRUTHSOCIAL_PASSWORD): %w", b.targetUsername, err)
+	}
+	log.Printf("✅ Truth Social OK: found @%s (%d followers)", account.Username, account.FollowersCount)
+
+	if err := b.sendMessage(startupMessage); err != nil {
+		return fmt.Errorf("sending to Telegram chat %d failed (check TELEGRAM_BOT_TOKEN/TELEGRAM_CHAT_ID): %w", b.chatID, err)
+	}
+	log.Println("✅ Telegram OK: startup message delivered")
+
+	return nil
+}
+
 func (b *OrangeFeedBot) checkForNewPosts() {
-	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	if !b.checkMu.TryLock() {
+		log.Println("⏭️ Previous check still running, skipping")
+		return
+	}
+	defer b.checkMu.Unlock()
+	defer b.saveIfDirty()
+
+	paused := b.isPaused()
+	if paused && b.pauseSkips {
+		log.Println("⏸️ Monitoring paused, skipping this check")
+		return
+	}
+	if !paused {
+		b.sendQuietDigest(time.Now())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.fetchTimeout)
 	defer cancel()
 
+	if !b.breaker.allow(time.Now()) {
+		log.Printf("⏸️ Monitoring paused after repeated errors, next probe at %s", b.breaker.openUntil.Format(time.Kitchen))
+		return
+	}
+
 	// Fetch recent posts
-	statuses, err := b.truthClient.PullStatuses(ctx, b.targetUsername, true, 10)
+	statuses, err := b.fetchStatuses(ctx)
 	if err != nil {
 		log.Printf("❌ Error fetching posts: %v", err)
-		b.sendMessage(fmt.Sprintf("⚠️ Error fetching posts from @%s: %v", b.targetUsername, err))
+		b.handleFetchFailure(err)
 		return
 	}
 
+	if b.breaker.recordSuccess() {
+		log.Println("▶️ Fetch succeeded again, resuming monitoring")
+		b.sendMessage(fmt.Sprintf("%s*Monitoring resumed* for @%s", b.outputStyle.mark("▶️", ""), b.targetUsername))
+	}
+
 	if len(statuses) == 0 {
 		log.Printf("📭 No posts found for @%s", b.targetUsername)
 		return
 	}
 
+	if paused {
+		b.skipWhilePaused(statuses)
+		return
+	}
+
 	log.Printf("📄 Found %d posts to process", len(statuses))
 
-	// Process new posts (stop when we reach a post we've already processed)
+	if b.recordDir == "" {
+		b.processStatuses(ctx, statuses, time.Now())
+		return
+	}
+
+	// Snapshot the state first so the replay starts where this check did
+	tick := tickRecord{Time: time.Now(), Statuses: statuses}
+	b.stateMu.Lock()
+	tick.State, err = json.Marshal(b.state)
+	b.stateMu.Unlock()
+	if err != nil {
+		log.Printf("❌ Error encoding state for the recording: %v", err)
+	}
+	tick.Decisions = b.processStatuses(ctx, statuses, tick.Time)
+	b.recordTick(tick)
+}
+
+// processStatuses analyzes and sends the new posts among statuses, returning
+// what was decided for each post. The caller must hold checkMu.
+func (b *OrangeFeedBot) processStatuses(ctx context.Context, statuses []client.Status, now time.Time) []decision {
+	var decisions decisionLog
+
+	b.retryPending(ctx)
+
+	// Without a cursor this is the first check ever: only the newest
+	// backfillCount posts are analyzed, everything older is just marked seen
+	firstRun := b.state.LastPostID == ""
+	if firstRun {
+		log.Printf("🆕 First run, backfilling the %d most recent posts", b.backfillCount)
+	} else {
+		statuses = b.deferExcessPosts(statuses)
+	}
+
+	// Process new posts (anything not newer than the cursor was already processed)
 	newPostsCount := 0
-	for _, status := range statuses {
-		if status.ID == b.lastPostID {
-			break // We've reached posts we've already processed
+	newestID := b.state.LastPostID
+	for i, status := range statuses {
+		if !firstRun && !isNewerID(status.ID, b.state.LastPostID) {
+			decisions.skip(status.ID, "not newer than the cursor")
+			continue
+		}
+		if isNewerID(status.ID, newestID) {
+			newestID = status.ID
+		}
+
+		if b.state.SeenIDs.Contains(status.ID) {
+			decisions.skip(status.ID, "already seen")
+			continue // Already handled in an earlier check, even if out of order
+		}
+		b.state.SeenIDs.Add(status.ID)
+
+		if firstRun && i >= b.backfillCount {
+			decisions.skip(status.ID, "outside the backfill window")
+			continue // Older than the backfill window
+		}
+
+		if createdAt := publishedAt(status); b.maxPostAge > 0 && !createdAt.IsZero() && now.Sub(createdAt) > b.maxPostAge {
+			log.Printf("⌛ Skipping post %s: posted %s ago", status.ID, now.Sub(createdAt).Round(time.Minute))
+			decisions.skip(status.ID, "too old")
+			continue
 		}
 
 		// Clean and validate content
 		content := b.cleanContent(status.Content)
-		if len(content) < 10 {
-			continue // Skip very short posts
+		if len(content) < b.minContentLength {
+			log.Printf("⏭️ Skipping post %s: only %d characters of text", status.ID, len(content))
+			decisions.skip(status.ID, "too short")
+			continue
+		}
+
+		if b.isNearDuplicate(content) {
+			log.Printf("♻️ Skipping post %s: near-identical to a recent post", status.ID)
+			decisions.skip(status.ID, "near duplicate")
+			continue
 		}
 
 		log.Printf("🔍 Analyzing new post: %s", status.ID)
 
+		var notes []string
+		if b.ownTickers != nil && !b.sentimentOnly && b.ownTickers.mentionedIn(content) {
+			notes = append(notes, prompts.AuthorTickersNote(b.targetUsername, b.ownTickers.Tickers))
+		}
+
+		// A post that's quickly gaining likes and reblogs matters more
+		var engagement *engagementDelta
+		if b.engagementVelocity {
+			delta := b.engagementSince(status, now)
+			if delta.Period > 0 {
+				engagement = &delta
+				notes = append(notes, prompts.EngagementNote(delta.Likes, delta.Reblogs, formatPeriod(delta.Period)))
+			}
+		}
+
+		if note := b.authorNote(ctx, status.Account.Username); note != "" {
+			notes = append(notes, note)
+		}
+
+		// All caps and exclamation marks often go with market-moving posts
+		if feats := features.Extract(content); feats.Intensity >= intensityHintThreshold {
+			notes = append(notes, prompts.IntensityNote(int(feats.CapsRatio*100), feats.Exclamations))
+		}
+
+		// Earlier posts help with follow-ups to an ongoing story
+		if prior := b.priorPosts(statuses[i+1:]); len(prior) > 0 {
+			notes = append(notes, prompts.PriorPostsNote(prior))
+		}
+
+		// A post made while the market is closed can't move prices until the open
+		var nextOpen time.Time
+		if b.marketHoursAware {
+			if postedAt := postTime(status); !markethours.IsOpen(postedAt) {
+				nextOpen = markethours.NextOpen(postedAt)
+				notes = append(notes, prompts.OffHoursNote(formatMarketTime(nextOpen)))
+			}
+		}
+
+		// Posts the keywords rate as irrelevant get a neutral analysis for free.
+		if b.relevance != nil {
+			if relevant, score := b.relevance.Relevant(content); !relevant {
+				log.Printf("🪶 Post %s scored %.1f on the pre-filter, skipping the model", status.ID, score)
+				extras := alertExtras{engagement: engagement, nextOpen: nextOpen}
+				sent := b.handleAnalysis(ctx, status, content, prefilteredAnalysis(score), "", extras)
+				decisions.handled(status.ID, sent)
+				if sent {
+					newPostsCount++
+				}
+				continue
+			}
+		}
+
 		// Analyze the post
-		analysis, err := b.analyzer.AnalyzePost(content)
+		analysis, raw, err := b.analyzePost(ctx, content, notes)
 		if err != nil {
 			log.Printf("❌ Error analyzing post %s: %v", status.ID, err)
+			if raw != "" {
+				log.Printf("📄 Raw model response for %s: %s", status.ID, raw)
+			}
+
+			// Still forward the post; the analysis is retried on later checks
+			b.sendUnanalyzed(ctx, status, content, notes)
+			decisions.add(status.ID, "unanalyzed", err.Error())
 			continue
 		}
 
-		// Send analysis to Telegram
-		b.sendAnalysis(status, analysis)
-		newPostsCount++
+		sent := b.handleAnalysis(ctx, status, content, analysis, raw, alertExtras{engagement: engagement, nextOpen: nextOpen})
+		decisions.handled(status.ID, sent)
+		if sent {
+			newPostsCount++
+		}
 	}
 
+	b.state.LastPostID = newestID
+	if b.engagementVelocity {
+		b.snapshotEngagement(statuses, now)
+	}
 	if newPostsCount > 0 {
-		b.lastPostID = statuses[0].ID
 		log.Printf("✅ Processed %d new posts", newPostsCount)
 	} else {
 		log.Println("📭 No new posts to process")
 	}
+
+	b.saveState()
+	return decisions
+}
+
+// handleAnalysis records a successful analysis and sends it unless a
+// filter rules it out, reporting whether it was sent
+func (b *OrangeFeedBot) handleAnalysis(ctx context.Context, status client.Status, content string, analysis *analyzer.Analysis, raw string, extras alertExtras) bool {
+	if analysis.DroppedEntries > 0 {
+		log.Printf("🧽 Dropped %d duplicate sectors or invalid tickers from the analysis of %s", analysis.DroppedEntries, status.ID)
+	}
+
+	b.recordAnalysis(ctx, status, content, analysis, raw)
+	b.rememberContent(content)
+	b.trackSentiment(analysis)
+
+	if len(b.watchlist) > 0 && !analysis.Matches(b.watchlist) {
+		log.Printf("🙈 Post %s doesn't touch the watchlist, not sending", status.ID)
+		return false
+	}
+
+	if len(b.alertSignals) > 0 && !slices.Contains(b.alertSignals, analysis.TradingSignal) {
+		log.Printf("🔕 Post %s has a %s signal, not sending", status.ID, analysis.TradingSignal)
+		return false
+	}
+
+	// Send analysis to Telegram
+	b.sendAnalysis(ctx, status, analysis, extras)
+	return true
+}
+
+// analyzePost asks the model about a post. Posts naming the author's own
+// company list its tickers.
+func (b *OrangeFeedBot) analyzePost(ctx context.Context, content string, notes []string) (*analyzer.Analysis, string, error) {
+	analysis, raw, err := b.analyzer.AnalyzePostRaw(ctx, content, notes...)
+	if err == nil && b.ownTickers != nil && !b.sentimentOnly && b.ownTickers.mentionedIn(content) {
+		analysis.AddStocks(b.ownTickers.Tickers...)
+	}
+	return analysis, raw, err
+}
+
+// deferExcessPosts leaves out the newest new posts beyond maxPostsPerTick.
+// The cursor then stops short of them, so the next check picks them up and
+// posts are always analyzed oldest first.
+func (b *OrangeFeedBot) deferExcessPosts(statuses []client.Status) []client.Status {
+	if b.maxPostsPerTick <= 0 {
+		return statuses
+	}
+
+	var fresh []string
+	for _, status := range statuses {
+		if !isNewerID(status.ID, b.state.LastPostID) || b.state.SeenIDs.Contains(status.ID) {
+			continue
+		}
+		fresh = append(fresh, status.ID)
+	}
+	if len(fresh) <= b.maxPostsPerTick {
+		return statuses
+	}
+
+	// Newest first, so the posts to defer come first
+	slices.SortFunc(fresh, func(x, y string) int {
+		if isNewerID(x, y) {
+			return -1
+		}
+		if isNewerID(y, x) {
+			return 1
+		}
+		return 0
+	})
+	deferred := make(map[string]bool)
+	for _, id := range fresh[:len(fresh)-b.maxPostsPerTick] {
+		deferred[id] = true
+	}
+
+	log.Printf("🚦 %d new posts, analyzing the oldest %d and deferring the rest to the next check", len(fresh), b.maxPostsPerTick)
+	return slices.DeleteFunc(slices.Clone(statuses), func(status client.Status) bool {
+		return deferred[status.ID]
+	})
 }
 
-func (b *OrangeFeedBot) sendAnalysis(status client.Status, analysis *analyzer.Analysis) {
-	content := b.cleanContent(status.Content)
+// noDefaultPatterns is a strip patterns file line dropping the built-in
+// patterns, so the file replaces them rather than adding to them
+const noDefaultPatterns = "!no-defaults"
+
+// loadStripPatterns reads one regular expression per line, skipping blank
+// lines and "#" comments, and returns them after the built-in patterns.
+// A noDefaultPatterns line leaves the built-in ones out; on its own it
+// turns stripping off.
+func loadStripPatterns(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read strip patterns file: %w", err)
+	}
+
+	defaults := true
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == noDefaultPatterns:
+			defaults = false
+		case line != "" && !strings.HasPrefix(line, "#"):
+			patterns = append(patterns, line)
+		}
+	}
+	if defaults {
+		patterns = append(slices.Clone(htmltext.DefaultStripPatterns), patterns...)
+	}
+	return patterns, nil
+}
 
-	// Create concise analysis message
-	message := fmt.Sprintf(`🚨 *NEW POST* | %s (%.0f%%)
+// priorPosts returns the cleaned text of up to contextPosts posts from
+// older, which is ordered newest first
+func (b *OrangeFeedBot) priorPosts(older []client.Status) []string {
+	var posts []string
+	for _, status := range older {
+		if len(posts) == b.contextPosts {
+			break
+		}
+		if content := b.cleanContent(status.Content); content != "" {
+			posts = append(posts, content)
+		}
+	}
+	return posts
+}
 
-📝 %s
+// intensityHintThreshold is the features.Text Intensity from which the model
+// is told the post is shouted
+const intensityHintThreshold = 0.3
 
-📊 %s %s | %s | %s risk
-🏭 %s | 📈 %s
+// prefilteredAnalysis stands in for the model's analysis of a post the
+// pre-filter found irrelevant to markets
+func prefilteredAnalysis(score float64) *analyzer.Analysis {
+	return &analyzer.Analysis{
+		Summary:           fmt.Sprintf("No market-relevant topics found (pre-filter score %.1f), not analyzed.", score),
+		MarketImpact:      "neutral",
+		TradingSignal:     "hold",
+		TimeHorizon:       "long-term",
+		RiskLevel:         "low",
+		ExpectedMagnitude: "minimal",
+	}
+}
 
-💡 %s`,
-		strings.ToUpper(analysis.MarketImpact),
-		analysis.Confidence*100,
-		b.escapeMarkdown(content),
-		getSignalEmoji(analysis.TradingSignal),
-		strings.ToUpper(analysis.TradingSignal),
-		analysis.TimeHorizon,
-		strings.ToUpper(analysis.RiskLevel),
-		formatList(analysis.AffectedSectors, 2),
-		formatList(analysis.SpecificStocks, 3),
-		b.escapeMarkdown(analysis.Summary))
+// isNearDuplicate reports whether content is a trivially changed repost of a
+// recently analyzed post. It is disabled when no threshold is configured.
+func (b *OrangeFeedBot) isNearDuplicate(content string) bool {
+	if b.similarityThreshold <= 0 {
+		return false
+	}
 
-	// Add actionable insights if available (keep it very short)
-	if len(analysis.ActionableInsights) > 0 && len(analysis.ActionableInsights[0]) > 0 {
-		message += fmt.Sprintf("\n⚡ %s", b.escapeMarkdown(analysis.ActionableInsights[0]))
+	for _, previous := range b.state.RecentContents {
+		if textsim.Jaccard(content, previous) >= b.similarityThreshold {
+			return true
+		}
 	}
+	return false
+}
 
-	// Add minimal post metadata
-	message += fmt.Sprintf("\n\n🔗 [View](%s) | 👍 %d | 🔄 %d",
-		status.URL,
-		status.FavouritesCount,
-		status.ReblogsCount)
+func (b *OrangeFeedBot) rememberContent(content string) {
+	if b.similarityThreshold <= 0 {
+		return
+	}
 
-	b.sendMessage(message)
+	b.state.RecentContents = append(b.state.RecentContents, content)
+	if len(b.state.RecentContents) > b.similarityHistory {
+		b.state.RecentContents = b.state.RecentContents[len(b.state.RecentContents)-b.similarityHistory:]
+	}
+}
+
+// fetchLimit is how many of the latest posts each check fetches
+const fetchLimit = 10
+
+// fetchStatuses pulls the latest posts, retrying transient failures within
+// the check so breaking news isn't delayed until the next tick.
+// Authentication errors aren't retried; the client re-authenticates on its
+// own.
+func (b *OrangeFeedBot) fetchStatuses(ctx context.Context) ([]client.Status, error) {
+	for attempt := 0; ; attempt++ {
+		statuses, err := b.pullStatuses(ctx, b.targetUsername, true, fetchLimit)
+		if err == nil || attempt == b.fetchRetries || isAuthError(err) {
+			return statuses, err
+		}
+
+		log.Printf("🔁 Fetch failed (%v), retrying in %s", err, b.fetchRetryDelay)
+		select {
+		case <-time.After(b.fetchRetryDelay):
+		case <-ctx.Done():
+			return nil, err
+		}
+	}
+}
+
+// isAuthError reports whether a fetch failed because the session was
+// rejected. The client doesn't type its errors, so this goes by the message.
+func isAuthError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"401", "unauthorized", "invalid_token", "invalid_grant"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleFetchFailure reports a failed fetch, pausing monitoring via the
+// circuit breaker instead of reporting every failure once errors pile up
+func (b *OrangeFeedBot) handleFetchFailure(err error) {
+	alreadyTripped := b.breaker.tripped()
+	if !b.breaker.recordFailure(time.Now()) {
+		b.sendMessage(fmt.Sprintf("%sError fetching posts from @%s: %v", b.outputStyle.mark("⚠️", ""), b.targetUsername, err))
+		return
+	}
+
+	log.Printf("⏸️ Pausing monitoring for %s after %d consecutive failures", b.breaker.cooldown, b.breaker.failures)
+	if !alreadyTripped {
+		b.sendMessage(fmt.Sprintf("%s*Monitoring paused due to repeated errors*\n\nLast error: %v\nRetrying in %s.",
+			b.outputStyle.mark("⏸️", ""), err, b.breaker.cooldown))
+	}
+}
+
+// saveState saves the state. It must not be called with stateMu held, and
+// only from a check or with checkMu held, so the state doesn't change while
+// it is written.
+func (b *OrangeFeedBot) saveState() {
+	b.stateMu.Lock()
+	defer b.stateMu.Unlock()
+
+	b.stateDirty = false
+	if err := b.stateStore.Save(b.state); err != nil {
+		log.Printf("❌ Error saving state: %v", err)
+	}
+}
+
+// updateState applies a change to the state under stateMu and reports
+// whether anything changed. The change is saved right away, or by the
+// running check when there is one.
+func (b *OrangeFeedBot) updateState(change func(state *botState) bool) bool {
+	b.stateMu.Lock()
+	changed := change(b.state)
+	b.stateDirty = b.stateDirty || changed
+	b.stateMu.Unlock()
+
+	if changed && b.checkMu.TryLock() {
+		b.saveState()
+		b.checkMu.Unlock()
+	}
+	return changed
+}
+
+// saveIfDirty saves what commands changed while a check ran. The caller must
+// hold checkMu.
+func (b *OrangeFeedBot) saveIfDirty() {
+	b.stateMu.Lock()
+	dirty := b.stateDirty
+	b.stateMu.Unlock()
+
+	if dirty {
+		b.saveState()
+	}
+}
+
+// isPaused reports whether monitoring was paused with /pause
+func (b *OrangeFeedBot) isPaused() bool {
+	b.stateMu.Lock()
+	defer b.stateMu.Unlock()
+	return b.state.Paused
+}
+
+// recordAnalysis keeps the analysis for the "Details" button and stores it in
+// the database, when one is configured
+func (b *OrangeFeedBot) recordAnalysis(ctx context.Context, status client.Status, content string, analysis *analyzer.Analysis, raw string) {
+	b.recent.add(status.ID, analysis)
+	if b.db == nil {
+		return
+	}
+
+	statusJSON, err := json.Marshal(status)
+	if err != nil {
+		log.Printf("❌ Error encoding post %s: %v", status.ID, err)
+		return
+	}
+
+	err = b.db.SaveAnalysis(ctx, store.Record{
+		PostID:      status.ID,
+		Username:    b.targetUsername,
+		Content:     content,
+		URL:         status.URL,
+		CreatedAt:   publishedAt(status).Format(time.RFC3339),
+		Status:      statusJSON,
+		Analysis:    analysis,
+		RawResponse: raw,
+	})
+	if err != nil {
+		log.Printf("❌ Error storing analysis: %v", err)
+	}
+}
+
+// auditRecord is one line of the audit log
+type auditRecord struct {
+	Timestamp time.Time          `json:"timestamp"`
+	PostID    string             `json:"post_id"`
+	PostURL   string             `json:"post_url"`
+	Analysis  *analyzer.Analysis `json:"analysis"`
+}
+
+// audit appends an outgoing alert to the audit log, when one is configured
+func (b *OrangeFeedBot) audit(status client.Status, url string, analysis *analyzer.Analysis) {
+	if b.auditLog == nil {
+		return
+	}
+
+	err := b.auditLog.Append(auditRecord{
+		Timestamp: time.Now().UTC(),
+		PostID:    status.ID,
+		PostURL:   url,
+		Analysis:  analysis,
+	})
+	if err != nil {
+		log.Printf("❌ Error writing audit log: %v", err)
+	}
+}
+
+// sendAnalysis formats an analysis in the configured style and sends it to
+// every enabled destination
+func (b *OrangeFeedBot) sendAnalysis(ctx context.Context, status client.Status, analysis *analyzer.Analysis, extras alertExtras) {
+	msg := notify.Message{
+		PostID:   status.ID,
+		PostURL:  status.URL,
+		Content:  b.cleanContent(status.Content),
+		Text:     b.formatAnalysis(status, analysis, b.outputStyle, extras),
+		Analysis: analysis,
+		Silent:   alertPriority(analysis) < b.silentBelow,
+	}
+
+	if b.alreadyDelivered(status.ID) {
+		log.Printf("⏭️ Alert for post %s was already sent, not sending it again", status.ID)
+		return
+	}
+
+	if b.quietHours != nil && b.quietHours.contains(time.Now()) {
+		b.holdAlert(status, analysis)
+		return
+	}
+
+	b.audit(status, status.URL, analysis)
+	b.notify(ctx, msg)
+}
+
+// authorNote describes the post's author for the prompt, when enabled
+func (b *OrangeFeedBot) authorNote(ctx context.Context, username string) string {
+	if b.profiles == nil || username == "" {
+		return ""
+	}
+
+	account, err := b.profiles.get(ctx, username)
+	if err != nil || account == nil {
+		log.Printf("⚠️ Could not look up @%s for author context: %v", username, err)
+		return ""
+	}
+
+	return prompts.AuthorNote(account.Username, compactCount(account.FollowersCount), account.Verified)
+}
+
+// postTime is when the post was published, or now when the timestamp is missing
+func postTime(status client.Status) time.Time {
+	if postedAt := publishedAt(status); !postedAt.IsZero() {
+		return postedAt
+	}
+	return time.Now()
+}
+
+// formatDisplayTime renders a time in the display zone, e.g. "Jan 2 15:04 EST"
+func (b *OrangeFeedBot) formatDisplayTime(t time.Time) string {
+	return t.In(b.displayZone).Format("Jan 2 15:04 MST")
+}
+
+// formatMarketTime renders a time in exchange time, e.g. "Mon 09:30 ET"
+func formatMarketTime(t time.Time) string {
+	return markethours.InExchangeTime(t).Format("Mon 15:04") + " ET"
+}
+
+// signalText renders the trading signal, e.g. "🟢 BUY", or nothing in
+// sentiment-only mode
+func (b *OrangeFeedBot) signalText(analysis *analyzer.Analysis, style outputStyle) string {
+	if b.sentimentOnly {
+		return ""
+	}
+	if style == stylePlain {
+		return strings.ToUpper(analysis.TradingSignal)
+	}
+	return getSignalEmoji(analysis.TradingSignal) + " " + strings.ToUpper(analysis.TradingSignal)
 }
 
 // Helper function to get emoji for trading signal
@@ -285,64 +1398,18 @@ func formatList(items []string, maxItems int) string {
 	return strings.Join(items[:maxItems], ", ") + fmt.Sprintf(" +%d", len(items)-maxItems)
 }
 
-func (b *OrangeFeedBot) sendMessage(text string) {
-	msg := tgbotapi.NewMessage(b.chatID, text)
-	msg.ParseMode = "Markdown"
-	msg.DisableWebPagePreview = true
+func (b *OrangeFeedBot) sendMessage(text string) error {
+	return b.sendMessageTo(b.chatID, text)
+}
 
-	_, err := b.telegramBot.Send(msg)
+func (b *OrangeFeedBot) sendMessageTo(chatID int64, text string) error {
+	err := b.messenger.Send(chatID, text)
 	if err != nil {
 		log.Printf("❌ Error sending message: %v", err)
 	}
+	return err
 }
 
 func (b *OrangeFeedBot) cleanContent(content string) string {
-	// Simple HTML tag removal
-	content = strings.ReplaceAll(content, "<p>", "")
-	content = strings.ReplaceAll(content, "</p>", " ")
-	content = strings.ReplaceAll(content, "<br>", " ")
-	content = strings.ReplaceAll(content, "<br/>", " ")
-	content = strings.ReplaceAll(content, "<br />", " ")
-
-	// Remove other common HTML tags
-	for _, tag := range []string{"<a", "</a>", "<span", "</span>", "<div", "</div>"} {
-		if strings.Contains(content, tag) {
-			// Simple tag removal - find opening and closing
-			for strings.Contains(content, "<") && strings.Contains(content, ">") {
-				start := strings.Index(content, "<")
-				end := strings.Index(content[start:], ">")
-				if end == -1 {
-					break
-				}
-				content = content[:start] + content[start+end+1:]
-			}
-		}
-	}
-
-	return strings.TrimSpace(content)
-}
-
-func (b *OrangeFeedBot) escapeMarkdown(text string) string {
-	// Escape special Markdown characters
-	replacer := strings.NewReplacer(
-		"*", "\\*",
-		"_", "\\_",
-		"`", "\\`",
-		"[", "\\[",
-		"]", "\\]",
-		"(", "\\(",
-		")", "\\)",
-		"~", "\\~",
-		">", "\\>",
-		"#", "\\#",
-		"+", "\\+",
-		"-", "\\-",
-		"=", "\\=",
-		"|", "\\|",
-		"{", "\\{",
-		"}", "\\}",
-		".", "\\.",
-		"!", "\\!",
-	)
-	return replacer.Replace(text)
+	return b.cleaner.Clean(content)
 }
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"orangefeed/internal/analyzer"
+	"orangefeed/internal/htmltext"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+func TestBackfillWindow(t *testing.T) {
+	bot, _ := newTestBot(t)
+	bot.backfillCount = 2
+
+	// Short posts stop before the model, so the decisions show which were
+	// in the window
+	statuses := statusesFrom(t, `[
+		{"id": "205", "created_at": "2025-04-09T12:00:00.000Z", "content": "<p>short</p>"},
+		{"id": "204", "created_at": "2025-04-09T12:00:00.000Z", "content": "<p>short</p>"},
+		{"id": "203", "created_at": "2025-04-09T12:00:00.000Z", "content": "<p>short</p>"}
+	]`)
+
+	decisions := bot.processStatuses(context.Background(), statuses, time.Now())
+
+	want := map[string]string{
+		"205": "skipped (too short)",
+		"204": "skipped (too short)",
+		"203": "skipped (outside the backfill window)",
+	}
+	if len(decisions) != len(want) {
+		t.Fatalf("got %d decisions, want %d: %v", len(decisions), len(want), decisions)
+	}
+	for _, d := range decisions {
+		if got := describe(d); got != want[d.PostID] {
+			t.Errorf("post %s: got %q, want %q", d.PostID, got, want[d.PostID])
+		}
+	}
+
+	if bot.state.LastPostID != "205" {
+		t.Errorf("cursor = %q, want 205", bot.state.LastPostID)
+	}
+}
+
+func TestDeferExcessPostsOldestFirst(t *testing.T) {
+	bot, _ := newTestBot(t)
+	bot.maxPostsPerTick = 2
+	bot.state.LastPostID = "100"
+
+	statuses := statusesFrom(t, `[
+		{"id": "105", "created_at": "2025-04-09T12:05:00.000Z", "content": "<p>short</p>"},
+		{"id": "104", "created_at": "2025-04-09T12:04:00.000Z", "content": "<p>short</p>"},
+		{"id": "103", "created_at": "2025-04-09T12:03:00.000Z", "content": "<p>short</p>"},
+		{"id": "102", "created_at": "2025-04-09T12:02:00.000Z", "content": "<p>short</p>"},
+		{"id": "101", "created_at": "2025-04-09T12:01:00.000Z", "content": "<p>short</p>"},
+		{"id": "100", "created_at": "2025-04-09T12:00:00.000Z", "content": "<p>short</p>"}
+	]`)
+
+	// The same page comes back on every check until newer posts arrive
+	for _, want := range []struct {
+		handled []string
+		cursor  string
+	}{
+		{[]string{"102", "101"}, "102"},
+		{[]string{"104", "103"}, "104"},
+		{[]string{"105"}, "105"},
+		{nil, "105"},
+	} {
+		var handled []string
+		for _, d := range bot.processStatuses(context.Background(), statuses, time.Now()) {
+			if d.Reason == "too short" {
+				handled = append(handled, d.PostID)
+			}
+		}
+
+		if !slices.Equal(handled, want.handled) {
+			t.Errorf("handled %v, want %v", handled, want.handled)
+		}
+		if bot.state.LastPostID != want.cursor {
+			t.Errorf("cursor = %q, want %q", bot.state.LastPostID, want.cursor)
+		}
+	}
+}
+
+// tariffPosts are three long posts, newest first
+const tariffPosts = `[
+	{"id": "303", "created_at": "2025-04-09T12:03:00.000Z", "content": "<p>Tariffs on all foreign cars start next week, BIG changes!</p>"},
+	{"id": "302", "created_at": "2025-04-09T12:02:00.000Z", "content": "<p>Our auto workers will be protected like never before.</p>"},
+	{"id": "301", "created_at": "2025-04-09T12:01:00.000Z", "content": "<p>Detroit is coming back, thanks to the tariffs we put in.</p>"}
+]`
+
+func TestFirstRunSendsNothingByDefault(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.backfillCount = 0
+	fake := &fakeCompleter{reply: bearishReply}
+	bot.analyzer = analyzer.NewMarketAnalyzerWithClient(fake)
+
+	decisions := bot.processStatuses(context.Background(), statusesFrom(t, tariffPosts), time.Now())
+
+	if len(msgr.sent) != 0 || fake.calls != 0 {
+		t.Errorf("first run sent %d messages after %d analyses, want none", len(msgr.sent), fake.calls)
+	}
+	for _, d := range decisions {
+		if d.Reason != "outside the backfill window" {
+			t.Errorf("post %s: %s, want it outside the backfill window", d.PostID, describe(d))
+		}
+	}
+	if bot.state.LastPostID != "303" {
+		t.Errorf("cursor = %q, want the newest post", bot.state.LastPostID)
+	}
+
+	// The next check only handles posts published since
+	statuses := statusesFrom(t, `[{"id": "304", "created_at": "2025-04-09T12:04:00.000Z", "content": "<p>Tariffs on all foreign trucks start next week as well!</p>"}]`)
+	bot.processStatuses(context.Background(), append(statuses, statusesFrom(t, tariffPosts)...), time.Now())
+	if len(msgr.sent) != 1 || msgr.sent[0].Data != detailsPrefix+"304" {
+		t.Errorf("second check sent %v, want only post 304", msgr.sent)
+	}
+}
+
+func TestFirstRunBackfillsNewestPosts(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.backfillCount = 2
+
+	bot.processStatuses(context.Background(), statusesFrom(t, tariffPosts), time.Now())
+
+	var sent []string
+	for _, m := range msgr.sent {
+		sent = append(sent, strings.TrimPrefix(m.Data, detailsPrefix))
+	}
+	if !slices.Equal(sent, []string{"303", "302"}) {
+		t.Errorf("backfill sent %v, want the two newest posts", sent)
+	}
+}
+
+func TestOverlappingCheckIsSkipped(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.state.LastPostID = "300"
+
+	// A slow check still holds the lock when the next tick fires
+	bot.checkMu.Lock()
+	ticks := make(chan struct{})
+	go func() {
+		for range 3 {
+			bot.checkForNewPosts()
+		}
+		close(ticks)
+	}()
+
+	select {
+	case <-ticks:
+	case <-time.After(5 * time.Second):
+		t.Fatal("overlapping checks waited for the running one instead of skipping")
+	}
+	bot.checkMu.Unlock()
+
+	if len(msgr.sent) != 0 || bot.state.LastPostID != "300" {
+		t.Errorf("skipped checks sent %d messages and moved the cursor to %s", len(msgr.sent), bot.state.LastPostID)
+	}
+}
+
+func TestShortPostIsSkipped(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.state.LastPostID = "400"
+	bot.minContentLength = 10
+
+	statuses := statusesFrom(t, `[
+		{"id": "402", "created_at": "2025-04-09T12:02:00.000Z", "content": "<p>Tariffs on China!!</p>"},
+		{"id": "401", "created_at": "2025-04-09T12:01:00.000Z", "content": "<p>Tariffs!!</p>"}
+	]`)
+
+	decisions := bot.processStatuses(context.Background(), statuses, time.Now())
+
+	want := []string{"sent", "skipped (too short)"}
+	var got []string
+	for _, d := range decisions {
+		got = append(got, describe(d))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("decisions = %v, want %v", got, want)
+	}
+	if len(msgr.sent) != 1 || msgr.sent[0].Data != detailsPrefix+"402" {
+		t.Errorf("sent %v, want only the post long enough", msgr.sent)
+	}
+}
+
+func TestAlertSignalsFilter(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.state.LastPostID = "300"
+	bot.alertSignals = []string{"buy", "sell"}
+	bot.analyzer = analyzer.NewMarketAnalyzerWithClient(&fakeCompleter{
+		reply: `{"market_impact": "neutral", "confidence": 0.5, "summary": "Nothing new", "trading_signal": "HOLD"}`,
+	})
+
+	statuses := statusesFrom(t, tariffPosts)
+	decisions := bot.processStatuses(context.Background(), statuses, time.Now())
+
+	for _, d := range decisions {
+		if d.Action != "filtered" {
+			t.Errorf("post %s with a hold signal was %s", d.PostID, describe(d))
+		}
+	}
+	if len(msgr.sent) != 0 {
+		t.Errorf("sent %d alerts for hold signals", len(msgr.sent))
+	}
+	if bot.state.LastPostID != "303" {
+		t.Errorf("cursor = %q, want it past the filtered posts", bot.state.LastPostID)
+	}
+
+	for signal, want := range map[string]bool{"buy": true, "sell": true, "hold": false, "watch": false} {
+		analysis := &analyzer.Analysis{MarketImpact: "bearish", TradingSignal: signal}
+		if got := bot.handleAnalysis(context.Background(), statuses[0], "content", analysis, "", alertExtras{}); got != want {
+			t.Errorf("%s signal sent = %t, want %t", signal, got, want)
+		}
+	}
+}
+
+func TestMaxPostAge(t *testing.T) {
+	now := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	statuses := statusesFrom(t, `[
+		{"id": "503", "created_at": "2025-04-10T11:00:00.000Z", "content": "<p>Tariffs on all foreign cars start next week, BIG changes!</p>"},
+		{"id": "502", "created_at": "2025-04-09T12:30:00.000Z", "content": "<p>Our auto workers will be protected like never before.</p>"},
+		{"id": "501", "created_at": "2025-04-08T12:00:00.000Z", "content": "<p>Detroit is coming back, thanks to the tariffs we put in.</p>"}
+	]`)
+
+	tests := []struct {
+		maxAge time.Duration
+		want   []string
+	}{
+		{24 * time.Hour, []string{"sent", "sent", "skipped (too old)"}},
+		{time.Hour, []string{"sent", "skipped (too old)", "skipped (too old)"}},
+		{0, []string{"sent", "sent", "sent"}}, // Disabled
+	}
+
+	for _, tt := range tests {
+		bot, _ := newTestBot(t)
+		bot.backfillCount = 3
+		bot.maxPostAge = tt.maxAge
+
+		var got []string
+		for _, d := range bot.processStatuses(context.Background(), statuses, now) {
+			got = append(got, describe(d))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("MAX_POST_AGE=%s: decisions = %v, want %v", tt.maxAge, got, tt.want)
+		}
+		if bot.state.LastPostID != "503" {
+			t.Errorf("MAX_POST_AGE=%s: cursor = %q, want 503", tt.maxAge, bot.state.LastPostID)
+		}
+	}
+}
+
+// flakyFetch fails the first failures fetches with err, then returns
+// statuses
+func flakyFetch(statuses []client.Status, failures int, err error, calls *int) func(context.Context, string, bool, int) ([]client.Status, error) {
+	return func(ctx context.Context, username string, excludeReplies bool, limit int) ([]client.Status, error) {
+		*calls++
+		if *calls <= failures {
+			return nil, err
+		}
+		return statuses, nil
+	}
+}
+
+func TestFetchRetriesFlakyFetch(t *testing.T) {
+	bot, _ := newTestBot(t)
+	bot.fetchRetries = 2
+	bot.fetchRetryDelay = time.Millisecond
+	statuses := statusesFrom(t, tariffPosts)
+
+	calls := 0
+	bot.pullStatuses = flakyFetch(statuses, 1, errors.New("connection reset by peer"), &calls)
+	got, err := bot.fetchStatuses(context.Background())
+	if err != nil || len(got) != len(statuses) || calls != 2 {
+		t.Errorf("flaky fetch: %d posts, err %v after %d calls; want the posts on the second call", len(got), err, calls)
+	}
+
+	calls = 0
+	bot.pullStatuses = flakyFetch(statuses, 5, errors.New("connection reset by peer"), &calls)
+	if _, err := bot.fetchStatuses(context.Background()); err == nil || calls != 3 {
+		t.Errorf("failing fetch: err %v after %d calls, want an error after 3", err, calls)
+	}
+
+	calls = 0
+	bot.pullStatuses = flakyFetch(statuses, 1, errors.New("status 401: unauthorized"), &calls)
+	if _, err := bot.fetchStatuses(context.Background()); err == nil || calls != 1 {
+		t.Errorf("auth error: err %v after %d calls, want no retry", err, calls)
+	}
+}
+
+func TestFormatDisplayTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	bot, messenger := newTestBot(t)
+
+	tests := []struct {
+		zone *time.Location
+		at   time.Time
+		want string
+	}{
+		{time.UTC, time.Date(2025, 4, 10, 2, 30, 0, 0, time.UTC), "Apr 10 02:30 UTC"},
+		{newYork, time.Date(2025, 4, 10, 2, 30, 0, 0, time.UTC), "Apr 9 22:30 EDT"},
+		{newYork, time.Date(2025, 1, 10, 2, 30, 0, 0, time.UTC), "Jan 9 21:30 EST"},
+	}
+	for _, tt := range tests {
+		bot.displayZone = tt.zone
+		if got := bot.formatDisplayTime(tt.at); got != tt.want {
+			t.Errorf("formatDisplayTime(%s) in %s = %q, want %q", tt.at, tt.zone, got, tt.want)
+		}
+	}
+
+	status := statusesFrom(t, `[{"id": "1", "created_at": "2025-04-10T02:30:00.000Z", "content": "<p>Tariffs on all foreign cars!</p>"}]`)[0]
+	bot.sendAnalysis(context.Background(), status, &analyzer.Analysis{MarketImpact: "bearish"}, alertExtras{})
+	if len(messenger.sent) != 1 || !strings.Contains(messenger.sent[0].Text, "Apr 9 22:30 EDT") {
+		t.Errorf("alert doesn't show the post time in New York:\n%v", messenger.sent)
+	}
+}
+
+func TestLoadStripPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"adds to built-in", "# comment\n\n^Sent from\n", append(slices.Clone(htmltext.DefaultStripPatterns), "^Sent from")},
+		{"replaces built-in", "!no-defaults\n^Sent from\n", []string{"^Sent from"}},
+		{"disables stripping", "!no-defaults\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "strip_patterns.txt")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := loadStripPatterns(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
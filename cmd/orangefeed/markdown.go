@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// markdownV1Replacer escapes the characters legacy Markdown treats as
+// formatting. Escaping anything else would show the backslash.
+var markdownV1Replacer = strings.NewReplacer(
+	"_", "\\_",
+	"*", "\\*",
+	"`", "\\`",
+	"[", "\\[",
+)
+
+// markdownV2Replacer escapes every character MarkdownV2 reserves
+var markdownV2Replacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"_", "\\_",
+	"*", "\\*",
+	"[", "\\[",
+	"]", "\\]",
+	"(", "\\(",
+	")", "\\)",
+	"~", "\\~",
+	"`", "\\`",
+	">", "\\>",
+	"#", "\\#",
+	"+", "\\+",
+	"-", "\\-",
+	"=", "\\=",
+	"|", "\\|",
+	"{", "\\{",
+	"}", "\\}",
+	".", "\\.",
+	"!", "\\!",
+)
+
+// escapeMarkdownV1 makes text safe to send with the legacy Markdown mode
+func escapeMarkdownV1(text string) string {
+	return markdownV1Replacer.Replace(text)
+}
+
+// escapeMarkdownV2 makes text safe to send with MarkdownV2
+func escapeMarkdownV2(text string) string {
+	return markdownV2Replacer.Replace(text)
+}
+
+// escapeMarkdown escapes text for the parse mode messages are sent with
+func (b *OrangeFeedBot) escapeMarkdown(text string) string {
+	if parseMode == tgbotapi.ModeMarkdownV2 {
+		return escapeMarkdownV2(text)
+	}
+	return escapeMarkdownV1(text)
+}
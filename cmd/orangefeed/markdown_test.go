@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestEscapeMarkdownEachCharacter(t *testing.T) {
+	tests := []struct {
+		char   string
+		v1, v2 string
+	}{
+		{"_", `\_`, `\_`},
+		{"*", `\*`, `\*`},
+		{"`", "\\`", "\\`"},
+		{"[", `\[`, `\[`},
+		{"]", `]`, `\]`},
+		{"(", `(`, `\(`},
+		{")", `)`, `\)`},
+		{"~", `~`, `\~`},
+		{">", `>`, `\>`},
+		{"#", `#`, `\#`},
+		{"+", `+`, `\+`},
+		{"-", `-`, `\-`},
+		{"=", `=`, `\=`},
+		{"|", `|`, `\|`},
+		{"{", `{`, `\{`},
+		{"}", `}`, `\}`},
+		{".", `.`, `\.`},
+		{"!", `!`, `\!`},
+		{`\`, `\`, `\\`},
+		{"$", `$`, `$`},
+		{"%", `%`, `%`},
+		{"&", `&`, `&`},
+	}
+
+	for _, tt := range tests {
+		text := "a" + tt.char + "b"
+		if got, want := escapeMarkdownV1(text), "a"+tt.v1+"b"; got != want {
+			t.Errorf("escapeMarkdownV1(%q) = %q, want %q", text, got, want)
+		}
+		if got, want := escapeMarkdownV2(text), "a"+tt.v2+"b"; got != want {
+			t.Errorf("escapeMarkdownV2(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"orangefeed/internal/notify"
+)
+
+// namedNotifier is an enabled alert destination
+type namedNotifier struct {
+	name string
+	notify.Notifier
+}
+
+// loadNotifiers builds the alert destinations listed in NOTIFIERS, which
+// defaults to Telegram alone
+func loadNotifiers(b *OrangeFeedBot) ([]namedNotifier, error) {
+	names := envList("NOTIFIERS")
+	if len(names) == 0 {
+		names = []string{"telegram"}
+	}
+
+	var notifiers []namedNotifier
+	for _, name := range names {
+		var notifier notify.Notifier
+		switch name {
+		case "telegram":
+			notifier = telegramNotifier{b: b}
+		case "discord":
+			url := os.Getenv("DISCORD_WEBHOOK_URL")
+			if url == "" {
+				return nil, fmt.Errorf("DISCORD_WEBHOOK_URL is required for the discord notifier")
+			}
+			notifier = notify.DiscordNotifier{WebhookURL: url}
+		case "webhook":
+			url := os.Getenv("WEBHOOK_URL")
+			if url == "" {
+				return nil, fmt.Errorf("WEBHOOK_URL is required for the webhook notifier")
+			}
+			notifier = notify.HTTPNotifier{URL: url}
+		default:
+			return nil, fmt.Errorf("invalid NOTIFIERS entry %q: must be telegram, discord or webhook", name)
+		}
+		notifiers = append(notifiers, namedNotifier{name: name, Notifier: notifier})
+	}
+
+	return notifiers, nil
+}
+
+// notify sends an alert to every enabled destination that hasn't had it yet,
+// retrying failed deliveries. Destinations are marked as delivered only once
+// they report success, and the marks are saved right away so a restart
+// mid-check can't resend.
+func (b *OrangeFeedBot) notify(ctx context.Context, msg notify.Message) {
+	delivered := false
+	for _, n := range b.notifiers {
+		key := deliveryKey(n.name, alertID(msg))
+		if b.state.Delivered.Contains(key) {
+			log.Printf("⏭️ Alert for post %s was already sent via %s", msg.PostID, n.name)
+			continue
+		}
+
+		if err := b.notifyWithRetries(ctx, n, msg); err != nil {
+			log.Printf("❌ Error sending alert via %s: %v", n.name, err)
+			continue
+		}
+		b.state.Delivered.Add(key)
+		delivered = true
+	}
+
+	if delivered {
+		b.saveState()
+	}
+}
+
+// notifyWithRetries delivers an alert through one destination, trying again
+// after sendRetryDelay when it fails. Telegram skips the chats that already
+// got the alert, so a retry only reaches the ones that failed.
+func (b *OrangeFeedBot) notifyWithRetries(ctx context.Context, n namedNotifier, msg notify.Message) error {
+	for attempt := 0; ; attempt++ {
+		err := n.Notify(ctx, msg)
+		if err == nil || attempt == b.sendRetries {
+			return err
+		}
+
+		log.Printf("🔁 Alert for post %s via %s failed (%v), retrying in %s", msg.PostID, n.name, err, b.sendRetryDelay)
+		select {
+		case <-time.After(b.sendRetryDelay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// alreadyDelivered reports whether every enabled destination has confirmed
+// the alert for postID
+func (b *OrangeFeedBot) alreadyDelivered(postID string) bool {
+	for _, n := range b.notifiers {
+		if !b.state.Delivered.Contains(deliveryKey(n.name, postID)) {
+			return false
+		}
+	}
+	return len(b.notifiers) > 0
+}
+
+// alertID identifies an alert for delivery bookkeeping. A post forwarded
+// without analysis gets its own ID, so the analysis still goes out once a
+// retry succeeds.
+func alertID(msg notify.Message) string {
+	if msg.Analysis == nil {
+		return msg.PostID + ":unanalyzed"
+	}
+	return msg.PostID
+}
+
+func deliveryKey(notifier, postID string) string {
+	return notifier + ":" + postID
+}
+
+// chatDeliveryKey marks an alert as delivered to one Telegram chat
+func chatDeliveryKey(chatID int64, postID string) string {
+	return deliveryKey(fmt.Sprintf("telegram:%d", chatID), postID)
+}
+
+// telegramNotifier delivers alerts to the Telegram chats they are routed
+// to and to subscribers, with a Details button. Each chat is marked as
+// delivered on success, so sending the alert again skips it.
+type telegramNotifier struct {
+	b *OrangeFeedBot
+}
+
+func (t telegramNotifier) Notify(ctx context.Context, msg notify.Message) error {
+	b := t.b
+
+	delivered := false
+	var errs []error
+	for _, target := range b.alertChats(routeTargets(b.routes, msg.Analysis, b.chatID)) {
+		chatID := target.ChatID
+		if b.state.Delivered.Contains(chatDeliveryKey(chatID, alertID(msg))) {
+			continue
+		}
+
+		opts := sendOptions{ThreadID: target.ThreadID, Silent: msg.Silent}
+		err := b.messenger.SendWithButton(chatID, msg.Text, b.outputStyle.mark("🔎", "")+"Details", detailsPrefix+msg.PostID, opts)
+		if err != nil && isBlockedError(err) && b.removeSubscriber(chatID) {
+			log.Printf("🧹 Removed subscriber %d, the bot can no longer message it", chatID)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("chat %d: %w", chatID, err))
+			continue
+		}
+		b.state.Delivered.Add(chatDeliveryKey(chatID, alertID(msg)))
+		delivered = true
+	}
+	if delivered {
+		b.saveState()
+	}
+
+	return errors.Join(errs...)
+}
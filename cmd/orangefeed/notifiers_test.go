@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"orangefeed/internal/analyzer"
+	"orangefeed/internal/notify"
+)
+
+func TestNotifyRetriesOnlyFailedChats(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.state.Subscribers = []int64{200}
+	bot.sendRetries = 1
+
+	// The subscriber's first send times out: it may or may not have arrived,
+	// but the primary chat's certainly did and must not get a second copy
+	attempts := map[int64]int{}
+	msgr.fail = func(chatID int64) error {
+		attempts[chatID]++
+		if chatID == 200 && attempts[chatID] == 1 {
+			return errors.New("context deadline exceeded")
+		}
+		return nil
+	}
+
+	msg := notify.Message{PostID: "111", Text: "alert", Analysis: &analyzer.Analysis{}}
+	bot.notify(context.Background(), msg)
+
+	if attempts[100] != 1 || attempts[200] != 2 {
+		t.Errorf("attempts = %v, want one to 100 and two to 200", attempts)
+	}
+	if len(msgr.sent) != 2 {
+		t.Fatalf("sent %d messages, want 2: %v", len(msgr.sent), msgr.sent)
+	}
+	if !bot.alreadyDelivered("111") {
+		t.Error("alert isn't marked as delivered after the retry succeeded")
+	}
+
+	// Sending the same post again, as after a restart, reaches no one
+	bot.notify(context.Background(), msg)
+	if len(msgr.sent) != 2 {
+		t.Errorf("sent %d messages after a repeat, want still 2", len(msgr.sent))
+	}
+}
+
+func TestNotifyResumesPartialDelivery(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.state.Subscribers = []int64{200}
+
+	down := true
+	msgr.fail = func(chatID int64) error {
+		if chatID == 200 && down {
+			return errors.New("Bad Gateway")
+		}
+		return nil
+	}
+
+	msg := notify.Message{PostID: "111", Text: "alert", Analysis: &analyzer.Analysis{}}
+	bot.notify(context.Background(), msg)
+	if bot.alreadyDelivered("111") {
+		t.Fatal("alert is marked as delivered although a chat failed")
+	}
+
+	down = false
+	bot.notify(context.Background(), msg)
+
+	var chats []int64
+	for _, m := range msgr.sent {
+		chats = append(chats, m.ChatID)
+	}
+	if len(chats) != 2 || chats[0] != 100 || chats[1] != 200 {
+		t.Errorf("sent to %v, want 100 once and then 200 once", chats)
+	}
+	if !bot.alreadyDelivered("111") {
+		t.Error("alert isn't marked as delivered after every chat got it")
+	}
+}
+
+func TestUnanalyzedPostReachesSubscribers(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.state.Subscribers = []int64{200}
+	status := statusesFrom(t, `[{"id": "111", "url": "https://truthsocial.com/@realDonaldTrump/111"}]`)[0]
+
+	bot.sendUnanalyzed(context.Background(), status, "Tariffs!", nil)
+	if len(msgr.sent) != 2 || msgr.sent[0].ChatID != 100 || msgr.sent[1].ChatID != 200 {
+		t.Fatalf("sent %v, want the post forwarded to 100 and 200", msgr.sent)
+	}
+	if len(bot.state.Pending) != 1 {
+		t.Errorf("%d posts pending, want the post queued for a retry", len(bot.state.Pending))
+	}
+
+	// The analysis from the retry still goes out
+	bot.notify(context.Background(), notify.Message{PostID: "111", Text: "alert", Analysis: &analyzer.Analysis{}})
+	if len(msgr.sent) != 4 {
+		t.Errorf("sent %d messages, want the analysis to follow to both chats", len(msgr.sent))
+	}
+}
+
+func TestUnanalyzedPostWaitsOutQuietHours(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	now := time.Now().UTC()
+	quiet, err := parseQuietHours(now.Add(-time.Hour).Format("15:04")+"-"+now.Add(time.Hour).Format("15:04"), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.quietHours = quiet
+	status := statusesFrom(t, `[{"id": "111"}]`)[0]
+
+	bot.sendUnanalyzed(context.Background(), status, "Tariffs!", nil)
+	if len(msgr.sent) != 0 {
+		t.Errorf("sent %v during quiet hours", msgr.sent)
+	}
+	if len(bot.state.Pending) != 1 {
+		t.Errorf("%d posts pending, want the post queued for a retry", len(bot.state.Pending))
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+func (b *OrangeFeedBot) handlePause(msg *tgbotapi.Message) {
+	b.setPaused(msg, true)
+}
+
+func (b *OrangeFeedBot) handleResume(msg *tgbotapi.Message) {
+	b.setPaused(msg, false)
+}
+
+// setPaused switches monitoring off or on, saves it with the state and
+// replies with the state monitoring is now in
+func (b *OrangeFeedBot) setPaused(msg *tgbotapi.Message, paused bool) {
+	changed := b.updateState(func(state *botState) bool {
+		if state.Paused == paused {
+			return false
+		}
+		state.Paused = paused
+		return true
+	})
+
+	if changed && paused {
+		log.Println("⏸️ Monitoring paused with /pause")
+	} else if changed {
+		log.Println("▶️ Monitoring resumed with /resume")
+	}
+
+	style := b.outputStyle
+	switch {
+	case paused && b.pauseSkips:
+		b.sendMessageTo(msg.Chat.ID, style.mark("⏸️", "")+"Monitoring is paused. Posts published meanwhile will be analyzed after /resume.")
+	case paused:
+		b.sendMessageTo(msg.Chat.ID, style.mark("⏸️", "")+"Monitoring is paused. Posts published meanwhile are skipped; send /resume to continue.")
+	default:
+		b.sendMessageTo(msg.Chat.ID, style.mark("▶️", "")+"Monitoring is running.")
+	}
+}
+
+// skipWhilePaused moves the cursor past statuses without analyzing or
+// sending anything. The caller must hold checkMu.
+func (b *OrangeFeedBot) skipWhilePaused(statuses []client.Status) {
+	for _, status := range statuses {
+		b.state.SeenIDs.Add(status.ID)
+		if b.state.LastPostID == "" || isNewerID(status.ID, b.state.LastPostID) {
+			b.state.LastPostID = status.ID
+		}
+	}
+
+	log.Printf("⏸️ Monitoring paused, skipped %d posts", len(statuses))
+	b.saveState()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"orangefeed/internal/notify"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+// maxPendingAttempts is how many retry passes a post whose analysis failed
+// gets before it is given up on
+const maxPendingAttempts = 3
+
+// pendingAnalysis is a post that was forwarded without analysis and is
+// retried on later checks
+type pendingAnalysis struct {
+	Status   client.Status `json:"status"`
+	Content  string        `json:"content"`
+	Notes    []string      `json:"notes,omitempty"`
+	Attempts int           `json:"attempts"`
+}
+
+// sendUnanalyzed forwards a post whose analysis failed so it isn't lost,
+// and queues it for another try. It goes wherever analyzed alerts go,
+// except during quiet hours: the analysis from a later retry is held for
+// the digest instead.
+func (b *OrangeFeedBot) sendUnanalyzed(ctx context.Context, status client.Status, content string, notes []string) {
+	b.state.Pending = append(b.state.Pending, pendingAnalysis{
+		Status:  status,
+		Content: content,
+		Notes:   notes,
+	})
+
+	if b.quietHours != nil && b.quietHours.contains(time.Now()) {
+		log.Printf("🤫 Quiet hours, not forwarding post %s without analysis", status.ID)
+		return
+	}
+
+	style := b.outputStyle
+	text := fmt.Sprintf("%s*NEW POST* | %sanalysis unavailable\n\n%s%s\n\n%s[View](%s)",
+		style.mark("🚨", ""),
+		style.mark("⚠️", "WARNING"),
+		style.mark("📝", "POST"),
+		b.escapeMarkdown(truncateQuote(content, b.maxQuoteLength)),
+		style.mark("🔗", ""),
+		status.URL)
+	b.notify(ctx, notify.Message{
+		PostID:  status.ID,
+		PostURL: status.URL,
+		Content: content,
+		Text:    text,
+	})
+}
+
+// retryPending analyzes the posts that were forwarded without analysis,
+// sending the analysis once it succeeds
+func (b *OrangeFeedBot) retryPending(ctx context.Context) {
+	if len(b.state.Pending) == 0 {
+		return
+	}
+
+	var still []pendingAnalysis
+	for _, pending := range b.state.Pending {
+		id := pending.Status.ID
+		analysis, raw, err := b.analyzePost(ctx, pending.Content, pending.Notes)
+		if err != nil {
+			pending.Attempts++
+			if pending.Attempts >= maxPendingAttempts {
+				log.Printf("🗑 Giving up on analyzing post %s after %d retries: %v", id, pending.Attempts, err)
+				b.addDeadLetter(pending, err)
+				continue
+			}
+			log.Printf("⏳ Retry %d for post %s failed: %v", pending.Attempts, id, err)
+			still = append(still, pending)
+			continue
+		}
+
+		log.Printf("🔁 Analyzed post %s on retry", id)
+		b.handleAnalysis(ctx, pending.Status, pending.Content, analysis, raw, alertExtras{})
+	}
+
+	b.state.Pending = still
+}
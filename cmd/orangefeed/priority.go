@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"orangefeed/internal/analyzer"
+)
+
+// magnitudeLevels ranks expected_magnitude values from least to most
+// important
+var magnitudeLevels = []string{"minimal", "moderate", "significant", "major"}
+
+// riskLevels are ranked on the same scale as magnitudeLevels
+var riskLevels = map[string]int{"low": 0, "medium": 1, "high": 2}
+
+// alertPriority rates an analysis on the magnitude scale, 0 (minimal) to 3
+// (major). A high risk level counts as at least a significant move.
+func alertPriority(analysis *analyzer.Analysis) int {
+	priority := 0
+	for i, level := range magnitudeLevels {
+		if strings.EqualFold(analysis.ExpectedMagnitude, level) {
+			priority = i
+		}
+	}
+	return max(priority, riskLevels[strings.ToLower(analysis.RiskLevel)])
+}
+
+// parsePriority reads a SILENT_BELOW value; empty means every alert notifies
+func parsePriority(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	for i, level := range magnitudeLevels {
+		if strings.EqualFold(value, level) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid SILENT_BELOW %q: must be one of %s", value, strings.Join(magnitudeLevels, ", "))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"orangefeed/internal/analyzer"
+)
+
+func TestAlertPriority(t *testing.T) {
+	tests := []struct {
+		magnitude, risk string
+		want            int
+	}{
+		{"minimal", "low", 0},
+		{"moderate", "low", 1},
+		{"Significant", "", 2},
+		{"major", "medium", 3},
+		{"minimal", "medium", 1},
+		{"minimal", "HIGH", 2}, // High risk counts as significant
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		got := alertPriority(&analyzer.Analysis{ExpectedMagnitude: tt.magnitude, RiskLevel: tt.risk})
+		if got != tt.want {
+			t.Errorf("alertPriority(%q, %q) = %d, want %d", tt.magnitude, tt.risk, got, tt.want)
+		}
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for value, want := range map[string]int{"": 0, "minimal": 0, "Moderate": 1, "significant": 2, "MAJOR": 3} {
+		if got, err := parsePriority(value); err != nil || got != want {
+			t.Errorf("parsePriority(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+	if _, err := parsePriority("huge"); err == nil {
+		t.Error("want an error for an unknown level")
+	}
+}
+
+func TestLowPriorityAlertsAreSilent(t *testing.T) {
+	bot, messenger := newTestBot(t)
+	bot.silentBelow = 2 // Significant
+	statuses := statusesFrom(t, `[
+		{"id": "1", "created_at": "2025-04-10T12:00:00.000Z", "content": "<p>Big changes coming for cars</p>"},
+		{"id": "2", "created_at": "2025-04-10T12:05:00.000Z", "content": "<p>Tariffs on all foreign cars!</p>"}
+	]`)
+
+	bot.sendAnalysis(context.Background(), statuses[0], &analyzer.Analysis{MarketImpact: "neutral", ExpectedMagnitude: "moderate", RiskLevel: "low"}, alertExtras{})
+	bot.sendAnalysis(context.Background(), statuses[1], &analyzer.Analysis{MarketImpact: "bearish", ExpectedMagnitude: "moderate", RiskLevel: "high"}, alertExtras{})
+
+	if len(messenger.sent) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(messenger.sent))
+	}
+	if !messenger.sent[0].Opts.Silent {
+		t.Error("moderate, low risk alert notified")
+	}
+	if messenger.sent[1].Opts.Silent {
+		t.Error("high risk alert was silent")
+	}
+}
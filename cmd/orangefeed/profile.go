@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+// profileCache remembers account lookups for a while so the author of every
+// post isn't looked up again on each check
+type profileCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	lookup  func(ctx context.Context, username string) (*client.Account, error)
+	now     func() time.Time
+	entries map[string]cachedProfile
+}
+
+type cachedProfile struct {
+	account   *client.Account
+	fetchedAt time.Time
+}
+
+func newProfileCache(ttl time.Duration, lookup func(ctx context.Context, username string) (*client.Account, error)) *profileCache {
+	return &profileCache{
+		ttl:     ttl,
+		lookup:  lookup,
+		now:     time.Now,
+		entries: make(map[string]cachedProfile),
+	}
+}
+
+// get returns the cached account, looking it up when missing or expired
+func (c *profileCache) get(ctx context.Context, username string) (*client.Account, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[username]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.fetchedAt) < c.ttl {
+		return entry.account, nil
+	}
+
+	account, err := c.lookup(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[username] = cachedProfile{account: account, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return account, nil
+}
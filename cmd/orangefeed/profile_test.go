@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+func TestProfileCacheTTL(t *testing.T) {
+	lookups := 0
+	cache := newProfileCache(time.Hour, func(ctx context.Context, username string) (*client.Account, error) {
+		lookups++
+		return &client.Account{Username: username, FollowersCount: lookups}, nil
+	})
+	now := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	get := func() *client.Account {
+		t.Helper()
+		account, err := cache.get(context.Background(), "realDonaldTrump")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return account
+	}
+
+	get()
+	now = now.Add(59 * time.Minute)
+	if account := get(); lookups != 1 || account.FollowersCount != 1 {
+		t.Errorf("lookups = %d within the TTL, want 1", lookups)
+	}
+
+	now = now.Add(time.Minute)
+	if account := get(); lookups != 2 || account.FollowersCount != 2 {
+		t.Errorf("lookups = %d after the TTL, want 2", lookups)
+	}
+
+	if _, err := cache.get(context.Background(), "someoneElse"); err != nil || lookups != 3 {
+		t.Errorf("lookups = %d for a second username, want 3", lookups)
+	}
+}
+
+func TestProfileCacheDoesNotCacheErrors(t *testing.T) {
+	lookups := 0
+	cache := newProfileCache(time.Hour, func(ctx context.Context, username string) (*client.Account, error) {
+		lookups++
+		if lookups == 1 {
+			return nil, errors.New("rate limited")
+		}
+		return &client.Account{Username: username}, nil
+	})
+
+	if _, err := cache.get(context.Background(), "realDonaldTrump"); err == nil {
+		t.Fatal("want the lookup error")
+	}
+	if _, err := cache.get(context.Background(), "realDonaldTrump"); err != nil || lookups != 2 {
+		t.Errorf("failed lookup was cached: err = %v, lookups = %d", err, lookups)
+	}
+}
+
+// lookupPayload is an abridged /api/v1/accounts/lookup response
+const lookupPayload = `{
+	"id": "107780257626128497",
+	"username": "realDonaldTrump",
+	"acct": "realDonaldTrump",
+	"display_name": "Donald J. Trump",
+	"locked": false,
+	"bot": false,
+	"created_at": "2022-02-11T16:16:57.705Z",
+	"note": "<p>45th &amp; 47th President of the United States of America🇺🇸</p>",
+	"url": "https://truthsocial.com/@realDonaldTrump",
+	"followers_count": 10234567,
+	"following_count": 71,
+	"statuses_count": 28790,
+	"verified": true,
+	"website": "www.DonaldJTrump.com"
+}`
+
+func TestAuthorNoteFromLookupPayload(t *testing.T) {
+	bot, _ := newTestBot(t)
+	bot.profiles = newProfileCache(time.Hour, func(ctx context.Context, username string) (*client.Account, error) {
+		var account client.Account
+		err := json.Unmarshal([]byte(lookupPayload), &account)
+		return &account, err
+	})
+
+	got := bot.authorNote(context.Background(), "realDonaldTrump")
+	if want := "The author is @realDonaldTrump with 10.2M followers, verified"; got != want {
+		t.Errorf("authorNote = %q, want %q", got, want)
+	}
+}
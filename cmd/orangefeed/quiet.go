@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"orangefeed/internal/analyzer"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+// digestLimit is how many held alerts the quiet hours digest lists
+const digestLimit = 10
+
+// quietHours is a daily window during which alerts are held back. The
+// window may cross midnight, e.g. 22:00-07:00.
+type quietHours struct {
+	start, end int // Minutes after midnight; end is exclusive
+	loc        *time.Location
+}
+
+// parseQuietHours reads a "HH:MM-HH:MM" window in loc
+func parseQuietHours(value string, loc *time.Location) (*quietHours, error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid QUIET_HOURS %q: expected HH:MM-HH:MM", value)
+	}
+
+	q := &quietHours{loc: loc}
+	for _, part := range []struct {
+		text   string
+		minute *int
+	}{{from, &q.start}, {to, &q.end}} {
+		t, err := time.Parse("15:04", strings.TrimSpace(part.text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUIET_HOURS %q: expected HH:MM-HH:MM", value)
+		}
+		*part.minute = t.Hour()*60 + t.Minute()
+	}
+
+	if q.start == q.end {
+		return nil, fmt.Errorf("invalid QUIET_HOURS %q: start and end are the same", value)
+	}
+	return q, nil
+}
+
+// contains reports whether t falls within the window
+func (q *quietHours) contains(t time.Time) bool {
+	t = t.In(q.loc)
+	minute := t.Hour()*60 + t.Minute()
+
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
+
+// heldAlert is an alert kept back during quiet hours, summarized in the
+// digest once they end
+type heldAlert struct {
+	PostID        string  `json:"post_id"`
+	URL           string  `json:"url"`
+	Summary       string  `json:"summary"`
+	MarketImpact  string  `json:"market_impact"`
+	Confidence    float64 `json:"confidence"`
+	TradingSignal string  `json:"trading_signal"`
+}
+
+// holdAlert keeps an alert for the digest instead of sending it
+func (b *OrangeFeedBot) holdAlert(status client.Status, analysis *analyzer.Analysis) {
+	log.Printf("🤫 Quiet hours, holding the alert for post %s", status.ID)
+
+	b.state.Held = append(b.state.Held, heldAlert{
+		PostID:        status.ID,
+		URL:           status.URL,
+		Summary:       analysis.Summary,
+		MarketImpact:  analysis.MarketImpact,
+		Confidence:    analysis.Confidence,
+		TradingSignal: analysis.TradingSignal,
+	})
+}
+
+// sendQuietDigest sends the alerts held during quiet hours as one message
+// to the primary chat and subscribers, once the window is over. The caller
+// must hold checkMu.
+func (b *OrangeFeedBot) sendQuietDigest(now time.Time) {
+	if len(b.state.Held) == 0 || (b.quietHours != nil && b.quietHours.contains(now)) {
+		return
+	}
+
+	held := b.state.Held
+	style := b.outputStyle
+
+	lines := []string{fmt.Sprintf("%s*%d posts while you were away*", style.mark("🌅", ""), len(held))}
+	for i, alert := range held {
+		if i == digestLimit {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(held)-digestLimit))
+			break
+		}
+
+		signal := ""
+		if !b.sentimentOnly {
+			signal = " | " + strings.ToUpper(alert.TradingSignal)
+		}
+		lines = append(lines, fmt.Sprintf("• %s (%.0f%%)%s - %s [View](%s)",
+			strings.ToUpper(alert.MarketImpact),
+			alert.Confidence*100,
+			signal,
+			b.escapeMarkdown(alert.Summary),
+			alert.URL))
+	}
+	text := strings.Join(lines, "\n\n")
+
+	for _, target := range b.alertChats([]alertTarget{{ChatID: b.chatID}}) {
+		b.sendMessageTo(target.ChatID, text)
+	}
+
+	log.Printf("🌅 Quiet hours over, sent a digest of %d held alerts", len(held))
+	b.state.Held = nil
+	b.saveState()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursAcrossMidnight(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	quiet, err := parseQuietHours("22:00-07:00", newYork)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		clock string // New York wall clock on 2025-04-10
+		want  bool
+	}{
+		{"21:59", false},
+		{"22:00", true},
+		{"23:59", true},
+		{"00:00", true},
+		{"03:00", true},
+		{"06:59", true},
+		{"07:00", false},
+		{"12:00", false},
+	}
+
+	for _, tt := range tests {
+		local, _ := time.ParseInLocation("2006-01-02 15:04", "2025-04-10 "+tt.clock, newYork)
+		if got := quiet.contains(local.UTC()); got != tt.want {
+			t.Errorf("contains(%s New York) = %t, want %t", tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestQuietHoursSameDay(t *testing.T) {
+	quiet, err := parseQuietHours("13:00-14:30", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for clock, want := range map[string]bool{"12:59": false, "13:00": true, "14:29": true, "14:30": false, "02:00": false} {
+		at, _ := time.Parse("15:04", clock)
+		if got := quiet.contains(at); got != want {
+			t.Errorf("contains(%s) = %t, want %t", clock, got, want)
+		}
+	}
+}
+
+func TestParseQuietHoursInvalid(t *testing.T) {
+	for _, value := range []string{"22:00", "22:00-25:00", "10pm-7am", "07:00-07:00"} {
+		if _, err := parseQuietHours(value, time.UTC); err == nil {
+			t.Errorf("parseQuietHours(%q): want an error", value)
+		}
+	}
+}
+
+func TestQuietDigestWaitsForWindowEnd(t *testing.T) {
+	bot, messenger := newTestBot(t)
+	bot.quietHours, _ = parseQuietHours("22:00-07:00", time.UTC)
+	bot.state.Held = []heldAlert{
+		{PostID: "1", URL: "https://truthsocial.com/1", Summary: "Auto tariffs", MarketImpact: "bearish", Confidence: 0.8, TradingSignal: "sell"},
+		{PostID: "2", URL: "https://truthsocial.com/2", Summary: "Energy deal", MarketImpact: "bullish", Confidence: 0.6, TradingSignal: "buy"},
+	}
+
+	bot.sendQuietDigest(time.Date(2025, 4, 11, 3, 0, 0, 0, time.UTC))
+	if len(messenger.sent) != 0 {
+		t.Fatalf("digest sent during quiet hours: %q", messenger.sent[0].Text)
+	}
+
+	bot.sendQuietDigest(time.Date(2025, 4, 11, 7, 0, 0, 0, time.UTC))
+	if len(messenger.sent) != 1 {
+		t.Fatalf("sent %d messages after quiet hours, want one digest", len(messenger.sent))
+	}
+	text := messenger.sent[0].Text
+	if !strings.Contains(text, "2 posts while you were away") || !strings.Contains(text, "BEARISH (80%) | SELL") {
+		t.Errorf("unexpected digest:\n%s", text)
+	}
+	if len(bot.state.Held) != 0 {
+		t.Errorf("%d alerts still held after the digest", len(bot.state.Held))
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+// tickRecord is one recorded check: what was fetched, the state it started
+// from and what happened to each post
+type tickRecord struct {
+	Time      time.Time       `json:"time"`
+	State     json.RawMessage `json:"state"`
+	Statuses  []client.Status `json:"statuses"`
+	Decisions []decision      `json:"decisions"`
+}
+
+// decision is what a check did with one post
+type decision struct {
+	PostID string `json:"post_id"`
+	Action string `json:"action"` // "sent", "filtered", "unanalyzed" or "skipped"
+	Reason string `json:"reason,omitempty"`
+}
+
+type decisionLog []decision
+
+func (d *decisionLog) add(postID, action, reason string) {
+	*d = append(*d, decision{PostID: postID, Action: action, Reason: reason})
+}
+
+func (d *decisionLog) skip(postID, reason string) {
+	d.add(postID, "skipped", reason)
+}
+
+// handled records the outcome of handleAnalysis
+func (d *decisionLog) handled(postID string, sent bool) {
+	if sent {
+		d.add(postID, "sent", "")
+	} else {
+		d.add(postID, "filtered", "")
+	}
+}
+
+// recordTick writes a check to RECORD_DIR, named after its start time so
+// the files sort in order
+func (b *OrangeFeedBot) recordTick(tick tickRecord) {
+	data, err := json.MarshalIndent(tick, "", "  ")
+	if err != nil {
+		log.Printf("❌ Error encoding recording: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(b.recordDir, 0o755); err != nil {
+		log.Printf("❌ Error creating %s: %v", b.recordDir, err)
+		return
+	}
+
+	name := filepath.Join(b.recordDir, "check-"+tick.Time.UTC().Format("20060102T150405.000Z")+".json")
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		log.Printf("❌ Error writing recording: %v", err)
+	}
+}
+
+// replay runs the recorded checks in path, a file or a directory of them,
+// through the analysis path and reports decisions that differ from the
+// recording. The state starts from the first recording and carries over.
+func (b *OrangeFeedBot) replay(path string) error {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return err
+	} else if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "check-*.json"))
+		if err != nil {
+			return err
+		}
+		sort.Strings(files)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no recordings found in %s", path)
+	}
+
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var tick tickRecord
+		if err := json.Unmarshal(data, &tick); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+
+		if i == 0 && len(tick.State) > 0 {
+			if b.state, err = decodeState(tick.State); err != nil {
+				return err
+			}
+		}
+
+		log.Printf("⏯️ Replaying %s: %d posts", filepath.Base(file), len(tick.Statuses))
+		ctx, cancel := context.WithTimeout(context.Background(), b.fetchTimeout)
+		b.checkMu.Lock()
+		decisions := b.processStatuses(ctx, tick.Statuses, tick.Time)
+		b.checkMu.Unlock()
+		cancel()
+
+		reportDifferences(tick.Decisions, decisions)
+	}
+
+	return nil
+}
+
+// reportDifferences logs every post whose replayed decision doesn't match
+// the recorded one
+func reportDifferences(recorded, replayed []decision) {
+	before := make(map[string]decision, len(recorded))
+	for _, d := range recorded {
+		before[d.PostID] = d
+	}
+
+	differences := 0
+	for _, d := range replayed {
+		if was, ok := before[d.PostID]; !ok || was.Action != d.Action || was.Reason != d.Reason {
+			log.Printf("🔀 Post %s: recorded %s, replayed %s %s", d.PostID, describe(was), d.Action, d.Reason)
+			differences++
+		}
+		delete(before, d.PostID)
+	}
+	for _, d := range before {
+		log.Printf("🔀 Post %s: recorded %s %s, not reached in the replay", d.PostID, d.Action, d.Reason)
+		differences++
+	}
+
+	if differences == 0 {
+		log.Println("✅ Replay matches the recording")
+	}
+}
+
+func describe(d decision) string {
+	if d.PostID == "" {
+		return "nothing"
+	}
+	if d.Reason == "" {
+		return d.Action
+	}
+	return d.Action + " (" + d.Reason + ")"
+}
+
+// discardStateStore starts from an empty state and never saves, so replays
+// leave the real state alone
+type discardStateStore struct{}
+
+func (discardStateStore) Load() (*botState, error) { return newBotState(), nil }
+func (discardStateStore) Save(*botState) error     { return nil }
+
+// replayMessenger logs what would have been sent to Telegram
+type replayMessenger struct{}
+
+func (r *replayMessenger) Send(chatID int64, text string) error {
+	log.Printf("📤 [replay] to %d:\n%s", chatID, text)
+	return nil
+}
+
+func (r *replayMessenger) SendPhoto(chatID int64, photo []byte, caption string) error {
+	log.Printf("📤 [replay] photo to %d:\n%s", chatID, caption)
+	return nil
+}
+
+func (r *replayMessenger) SendWithButton(chatID int64, text, label, data string, opts sendOptions) error {
+	log.Printf("📤 [replay] to %d (topic %d, silent %t):\n%s", chatID, opts.ThreadID, opts.Silent, text)
+	return nil
+}
+
+func (r *replayMessenger) Reply(chatID int64, replyTo int, text string) error {
+	log.Printf("📤 [replay] reply to %d in %d:\n%s", replyTo, chatID, text)
+	return nil
+}
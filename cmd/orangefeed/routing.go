@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"orangefeed/internal/analyzer"
+)
+
+// route sends analyses touching any of the Match patterns to ChatID, in the
+// forum topic ThreadID when set. Patterns are compared case-insensitively
+// against affected sectors and ticker symbols, and may use wildcards, e.g.
+// "tech*".
+type route struct {
+	Match    []string `json:"match"`
+	ChatID   int64    `json:"chat_id"`
+	ThreadID int      `json:"message_thread_id,omitempty"`
+}
+
+// alertTarget is a chat, and optionally a forum topic in it, to send an
+// alert to. ThreadID 0 is the chat's general topic.
+type alertTarget struct {
+	ChatID   int64
+	ThreadID int
+}
+
+// loadRoutes reads the routing rules from a JSON file
+func loadRoutes(filename string) ([]route, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routes file: %w", err)
+	}
+
+	var routes []route
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("failed to parse routes file: %w", err)
+	}
+
+	for i, r := range routes {
+		if r.ChatID == 0 {
+			return nil, fmt.Errorf("route %d has no chat_id", i)
+		}
+		if r.ThreadID < 0 {
+			return nil, fmt.Errorf("route %d has invalid message_thread_id %d", i, r.ThreadID)
+		}
+		for _, pattern := range r.Match {
+			if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+				return nil, fmt.Errorf("route %d has invalid pattern %q: %w", i, pattern, err)
+			}
+		}
+	}
+
+	return routes, nil
+}
+
+// routeTargets returns the default chat followed by every chat whose route
+// matches the analysis. Each chat gets one alert, in
+// the topic of its first matching route; the default chat falls back to its
+// general topic when no route for it matches.
+func routeTargets(routes []route, analysis *analyzer.Analysis, defaultChat int64) []alertTarget {
+	targets := []alertTarget{{ChatID: defaultChat}}
+	seen := map[int64]bool{}
+
+	// Posts forwarded without analysis have nothing to match on
+	var terms []string
+	if analysis != nil {
+		for _, sector := range analysis.AffectedSectors {
+			terms = append(terms, strings.ToLower(strings.TrimSpace(sector)))
+		}
+		for _, ticker := range analysis.SpecificStocks {
+			terms = append(terms, strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ticker), "$")))
+		}
+	}
+
+	for _, r := range routes {
+		if seen[r.ChatID] || !r.matches(terms) {
+			continue
+		}
+		seen[r.ChatID] = true
+
+		if r.ChatID == defaultChat {
+			targets[0].ThreadID = r.ThreadID
+			continue
+		}
+		targets = append(targets, alertTarget{ChatID: r.ChatID, ThreadID: r.ThreadID})
+	}
+
+	return targets
+}
+
+func (r route) matches(terms []string) bool {
+	for _, pattern := range r.Match {
+		pattern = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(pattern), "$"))
+		for _, term := range terms {
+			if ok, _ := path.Match(pattern, term); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"orangefeed/internal/analyzer"
+)
+
+// sentimentTracker keeps a rolling window of recent analyses and the
+// sentiment regime they add up to
+type sentimentTracker struct {
+	Window []*analyzer.Analysis `json:"window"`
+	Regime string               `json:"regime"`
+}
+
+func (t *sentimentTracker) add(analysis *analyzer.Analysis, size int) {
+	t.Window = append(t.Window, analysis)
+	if len(t.Window) > size {
+		t.Window = t.Window[len(t.Window)-size:]
+	}
+}
+
+// nextRegime applies hysteresis: the regime only flips once the score crosses
+// the opposite threshold, so scores hovering around zero don't cause flapping
+func nextRegime(current string, score, threshold float64) string {
+	switch {
+	case score >= threshold:
+		return "bullish"
+	case score <= -threshold:
+		return "bearish"
+	case current == "":
+		return "neutral"
+	default:
+		return current
+	}
+}
+
+// trackSentiment adds an analysis to the rolling window and alerts when the
+// aggregate sentiment flips between bullish and bearish
+func (b *OrangeFeedBot) trackSentiment(analysis *analyzer.Analysis) {
+	if b.sentimentWindow <= 0 {
+		return
+	}
+
+	tracker := &b.state.Sentiment
+	tracker.add(analysis, b.sentimentWindow)
+
+	score := b.analyzer.GetMarketSentimentScore(tracker.Window)
+	previous := tracker.Regime
+	tracker.Regime = nextRegime(previous, score, b.sentimentThreshold)
+
+	if previous == tracker.Regime || previous == "neutral" || previous == "" {
+		return
+	}
+
+	log.Printf("🔀 Sentiment regime shifted from %s to %s (score %.2f)", previous, tracker.Regime, score)
+	arrow := "➡️"
+	if b.outputStyle == stylePlain {
+		arrow = "to"
+	}
+	b.sendMessage(fmt.Sprintf(`%s*SENTIMENT SHIFT* | %s %s %s
+
+%sScore %+.2f over the last %d posts (majority: %s)`,
+		b.outputStyle.mark("🔀", ""),
+		strings.ToUpper(previous),
+		arrow,
+		strings.ToUpper(tracker.Regime),
+		b.outputStyle.mark("📊", ""),
+		score,
+		len(tracker.Window),
+		b.analyzer.GetMarketSentiment(tracker.Window)))
+}
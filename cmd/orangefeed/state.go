@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"orangefeed/internal/store"
+)
+
+// seenCapacity is how many processed status IDs are remembered across restarts
+const seenCapacity = 500
+
+// deliveredCapacity is how many post and notifier pairs, and post and
+// Telegram chat pairs, are remembered as delivered
+const deliveredCapacity = 10 * seenCapacity
+
+// botState is the part of the bot's memory that survives restarts
+type botState struct {
+	LastPostID string           `json:"last_post_id"`
+	SeenIDs    *seenSet         `json:"seen_ids"`
+	Sentiment  sentimentTracker `json:"sentiment"`
+
+	// Cleaned content of the most recently analyzed posts, oldest first
+	RecentContents []string `json:"recent_contents,omitempty"`
+
+	// Like and reblog counts of the posts fetched in the last check, by ID
+	Engagement map[string]engagementSnapshot `json:"engagement,omitempty"`
+
+	// Chats that opted into alerts with /subscribe
+	Subscribers []int64 `json:"subscribers,omitempty"`
+
+	// Posts forwarded without analysis because the model was unavailable
+	Pending []pendingAnalysis `json:"pending,omitempty"`
+
+	// Alerts held back during quiet hours, sent as a digest when they end
+	Held []heldAlert `json:"held,omitempty"`
+
+	// "notifier:postID" keys of alerts confirmed delivered, so a post that
+	// is processed again isn't alerted twice, and "telegram:chatID:postID"
+	// keys so a retried alert skips the chats that already got it
+	Delivered *seenSet `json:"delivered,omitempty"`
+
+	// Posts whose analysis kept failing after every retry, oldest first
+	DeadLetters []deadLetter `json:"dead_letters,omitempty"`
+
+	// Set with /pause: checks send nothing until /resume
+	Paused bool `json:"paused,omitempty"`
+}
+
+func newBotState() *botState {
+	return &botState{SeenIDs: newSeenSet(seenCapacity), Delivered: newSeenSet(deliveredCapacity)}
+}
+
+// stateStore persists the bot state between runs
+type stateStore interface {
+	Load() (*botState, error)
+	Save(state *botState) error
+}
+
+func decodeState(data []byte) (*botState, error) {
+	state := newBotState()
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+
+	if state.SeenIDs == nil {
+		state.SeenIDs = newSeenSet(seenCapacity)
+	}
+	if state.Delivered == nil {
+		state.Delivered = newSeenSet(deliveredCapacity)
+	}
+
+	return state, nil
+}
+
+// fileStateStore keeps the state in a JSON file
+type fileStateStore struct {
+	path string
+}
+
+// Load reads the state file, returning a fresh state if it doesn't exist yet
+func (f fileStateStore) Load() (*botState, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newBotState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	return decodeState(data)
+}
+
+// Save writes the state atomically so a crash mid-write can't corrupt it
+func (f fileStateStore) Save(state *botState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".orangefeed-state-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// dbStateKey is the key the state is stored under in the database
+const dbStateKey = "bot_state"
+
+// dbStateStore keeps the state in the SQLite database next to the analyses
+type dbStateStore struct {
+	db *store.Store
+}
+
+func (d dbStateStore) Load() (*botState, error) {
+	data, err := d.db.LoadState(context.Background(), dbStateKey)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return newBotState(), nil
+	}
+
+	return decodeState(data)
+}
+
+func (d dbStateStore) Save(state *botState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	return d.db.SaveState(context.Background(), dbStateKey, data)
+}
+
+// seenSet is a bounded set of status IDs backed by a ring buffer. Once full,
+// adding a new ID evicts the oldest one.
+type seenSet struct {
+	ring  []string
+	next  int
+	size  int
+	index map[string]struct{}
+}
+
+func newSeenSet(capacity int) *seenSet {
+	return &seenSet{
+		ring:  make([]string, capacity),
+		index: make(map[string]struct{}, capacity),
+	}
+}
+
+func (s *seenSet) Contains(id string) bool {
+	_, ok := s.index[id]
+	return ok
+}
+
+func (s *seenSet) Add(id string) {
+	if len(s.ring) == 0 || s.Contains(id) {
+		return
+	}
+
+	if s.size == len(s.ring) {
+		delete(s.index, s.ring[s.next])
+	} else {
+		s.size++
+	}
+
+	s.ring[s.next] = id
+	s.index[id] = struct{}{}
+	s.next = (s.next + 1) % len(s.ring)
+}
+
+// IDs returns the remembered IDs from oldest to newest
+func (s *seenSet) IDs() []string {
+	ids := make([]string, 0, s.size)
+	if s.size == 0 {
+		return ids
+	}
+
+	start := (s.next - s.size + len(s.ring)) % len(s.ring)
+	for i := 0; i < s.size; i++ {
+		ids = append(ids, s.ring[(start+i)%len(s.ring)])
+	}
+	return ids
+}
+
+func (s *seenSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.IDs())
+}
+
+func (s *seenSet) UnmarshalJSON(data []byte) error {
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return err
+	}
+
+	capacity := len(s.ring)
+	if capacity == 0 {
+		capacity = seenCapacity
+	}
+
+	*s = *newSeenSet(capacity)
+	for _, id := range ids {
+		s.Add(id)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestSeenSetEvictsOldest(t *testing.T) {
+	s := newSeenSet(3)
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		s.Add(id)
+	}
+
+	if got, want := s.IDs(), []string{"3", "4", "5"}; !slices.Equal(got, want) {
+		t.Errorf("IDs() = %v, want %v", got, want)
+	}
+	for _, id := range []string{"1", "2"} {
+		if s.Contains(id) {
+			t.Errorf("evicted ID %s is still contained", id)
+		}
+	}
+	for _, id := range []string{"3", "4", "5"} {
+		if !s.Contains(id) {
+			t.Errorf("ID %s is missing", id)
+		}
+	}
+}
+
+func TestSeenSetIgnoresRepeats(t *testing.T) {
+	s := newSeenSet(3)
+	for _, id := range []string{"1", "2", "1", "1", "3"} {
+		s.Add(id)
+	}
+
+	// Adding a known ID doesn't use up a slot, so nothing is evicted yet
+	if got, want := s.IDs(), []string{"1", "2", "3"}; !slices.Equal(got, want) {
+		t.Errorf("IDs() = %v, want %v", got, want)
+	}
+}
+
+func TestSeenSetRoundTrip(t *testing.T) {
+	s := newSeenSet(3)
+	for _, id := range []string{"1", "2", "3", "4"} {
+		s.Add(id)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `["2","3","4"]` {
+		t.Errorf("marshaled %s", data)
+	}
+
+	// The loaded set keeps its capacity and evicts in the saved order
+	loaded := newSeenSet(3)
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+	loaded.Add("5")
+	if got, want := loaded.IDs(), []string{"3", "4", "5"}; !slices.Equal(got, want) {
+		t.Errorf("IDs() after loading = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeStateFillsSeenSets(t *testing.T) {
+	state, err := decodeState([]byte(`{"last_post_id": "42"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// State files from before the seen-set existed still load
+	state.SeenIDs.Add("42")
+	if !state.SeenIDs.Contains("42") {
+		t.Error("SeenIDs isn't usable after loading an old state file")
+	}
+}
+
+// countingStateStore keeps the subscribers of every saved state
+type countingStateStore struct {
+	saved [][]int64
+}
+
+func (s *countingStateStore) Load() (*botState, error) { return newBotState(), nil }
+
+func (s *countingStateStore) Save(state *botState) error {
+	s.saved = append(s.saved, slices.Clone(state.Subscribers))
+	return nil
+}
+
+func TestCommandsDontWaitForCheck(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	store := &countingStateStore{}
+	bot.stateStore = store
+
+	// A check is running: the command goes through and the check saves it
+	bot.checkMu.Lock()
+	done := make(chan struct{})
+	go func() {
+		bot.handleSubscribe(&tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 200}})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("/subscribe waited for the running check")
+	}
+	if len(store.saved) != 0 || len(msgr.sent) != 1 {
+		t.Fatalf("saved %d times and replied %d times during the check, want only the reply", len(store.saved), len(msgr.sent))
+	}
+	bot.saveIfDirty()
+	bot.checkMu.Unlock()
+
+	if len(store.saved) != 1 || !slices.Equal(store.saved[0], []int64{200}) {
+		t.Errorf("saved %v after the check, want the new subscriber once", store.saved)
+	}
+
+	// Without a check the command saves right away
+	bot.handlePause(&tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 100}})
+	if len(store.saved) != 2 || !bot.isPaused() {
+		t.Errorf("paused = %t after %d saves, want paused and saved", bot.isPaused(), len(store.saved))
+	}
+}
+
+func TestDeadLettersDontWaitForCheck(t *testing.T) {
+	bot, msgr := newTestBot(t)
+	bot.state.DeadLetters = []deadLetter{{PostID: "111", URL: "https://truthsocial.com/@realDonaldTrump/111", Failures: 4, LastError: "server error"}}
+
+	bot.checkMu.Lock()
+	defer bot.checkMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		bot.handleDeadLetters(&tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 100}})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("/deadletters waited for the running check")
+	}
+	if len(msgr.sent) != 1 || !strings.Contains(msgr.sent[0].Text, "111") {
+		t.Errorf("sent %v, want the dead letter listed", msgr.sent)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/nicolas-martin/truthsocial-go/client"
+)
+
+// publishedAt parses when a post was published, which the client keeps as
+// the API's RFC 3339 string. It is zero when the timestamp is missing or
+// malformed.
+func publishedAt(status client.Status) time.Time {
+	if status.CreatedAt == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, status.CreatedAt)
+	if err != nil {
+		log.Printf("⚠️ Could not parse the time of post %s: %v", status.ID, err)
+		return time.Time{}
+	}
+	return t
+}
+
+// isNewerID reports whether status ID a is newer than b. IDs are
+// snowflake-like numeric strings, so they're compared as numbers.
+func isNewerID(a, b string) bool {
+	x, okA := new(big.Int).SetString(a, 10)
+	y, okB := new(big.Int).SetString(b, 10)
+	if !okA || !okB {
+		return a > b
+	}
+	return x.Cmp(y) > 0
+}
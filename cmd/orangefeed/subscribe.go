@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (b *OrangeFeedBot) handleSubscribe(msg *tgbotapi.Message) {
+	added := b.updateState(func(state *botState) bool {
+		if slices.Contains(state.Subscribers, msg.Chat.ID) {
+			return false
+		}
+		state.Subscribers = append(state.Subscribers, msg.Chat.ID)
+		return true
+	})
+
+	if !added {
+		b.sendMessageTo(msg.Chat.ID, b.outputStyle.mark("ℹ️", "")+"This chat is already subscribed to alerts.")
+		return
+	}
+
+	log.Printf("➕ Chat %d subscribed", msg.Chat.ID)
+	b.sendMessageTo(msg.Chat.ID, b.outputStyle.mark("✅", "")+"Subscribed! Alerts will be sent to this chat. Send /unsubscribe to stop.")
+}
+
+func (b *OrangeFeedBot) handleUnsubscribe(msg *tgbotapi.Message) {
+	if !b.removeSubscriber(msg.Chat.ID) {
+		b.sendMessageTo(msg.Chat.ID, b.outputStyle.mark("ℹ️", "")+"This chat isn't subscribed.")
+		return
+	}
+
+	log.Printf("➖ Chat %d unsubscribed", msg.Chat.ID)
+	b.sendMessageTo(msg.Chat.ID, b.outputStyle.mark("👋", "")+"Unsubscribed, no more alerts will be sent here.")
+}
+
+// removeSubscriber drops a chat from the broadcast list and saves the state
+func (b *OrangeFeedBot) removeSubscriber(chatID int64) bool {
+	return b.updateState(func(state *botState) bool {
+		i := slices.Index(state.Subscribers, chatID)
+		if i < 0 {
+			return false
+		}
+		state.Subscribers = slices.Delete(state.Subscribers, i, i+1)
+		return true
+	})
+}
+
+// alertChats adds the subscribers to the chats an alert is routed to
+func (b *OrangeFeedBot) alertChats(routed []alertTarget) []alertTarget {
+	b.stateMu.Lock()
+	subscribers := slices.Clone(b.state.Subscribers)
+	b.stateMu.Unlock()
+
+	targets := slices.Clone(routed)
+	for _, chatID := range subscribers {
+		routedHere := slices.ContainsFunc(targets, func(t alertTarget) bool { return t.ChatID == chatID })
+		if !routedHere {
+			targets = append(targets, alertTarget{ChatID: chatID})
+		}
+	}
+	return targets
+}
+
+// isBlockedError reports whether Telegram refused a message because the
+// user blocked the bot or the chat is gone for good
+func isBlockedError(err error) bool {
+	var tgErr *tgbotapi.Error
+	return errors.As(err, &tgErr) && tgErr.Code == http.StatusForbidden
+}
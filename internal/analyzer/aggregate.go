@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SectorCount is how often a sector was implicated and in which direction
+type SectorCount struct {
+	Name    string
+	Count   int
+	Bullish int
+	Bearish int
+}
+
+// TickerCount is how often a ticker was implicated
+type TickerCount struct {
+	Symbol string
+	Count  int
+}
+
+// AggregateReport summarizes a set of analyses, e.g. a week of posts
+type AggregateReport struct {
+	Posts             int
+	AverageConfidence float64
+	Sectors           []SectorCount // Most frequent first
+	Tickers           []TickerCount // Most frequent first
+}
+
+// Aggregate counts the sectors and tickers implicated by the analyses, with
+// the bullish/bearish split per sector and the average confidence
+func Aggregate(analyses []*Analysis) AggregateReport {
+	report := AggregateReport{Posts: len(analyses)}
+
+	sectors := make(map[string]*SectorCount)
+	tickers := make(map[string]*TickerCount)
+	totalConfidence := 0.0
+
+	for _, analysis := range analyses {
+		totalConfidence += analysis.Confidence
+		impact := strings.ToLower(analysis.MarketImpact)
+
+		// Count each sector/ticker once per post even if the model repeated it
+		seenSectors := make(map[string]bool)
+		for _, sector := range analysis.AffectedSectors {
+			key := strings.ToLower(strings.TrimSpace(sector))
+			if key == "" || seenSectors[key] {
+				continue
+			}
+			seenSectors[key] = true
+
+			count, ok := sectors[key]
+			if !ok {
+				count = &SectorCount{Name: strings.TrimSpace(sector)}
+				sectors[key] = count
+			}
+			count.Count++
+			switch impact {
+			case "bullish":
+				count.Bullish++
+			case "bearish":
+				count.Bearish++
+			}
+		}
+
+		seenTickers := make(map[string]bool)
+		for _, ticker := range analysis.SpecificStocks {
+			symbol := normalizeSymbol(ticker)
+			if symbol == "" || seenTickers[symbol] {
+				continue
+			}
+			seenTickers[symbol] = true
+
+			count, ok := tickers[symbol]
+			if !ok {
+				count = &TickerCount{Symbol: symbol}
+				tickers[symbol] = count
+			}
+			count.Count++
+		}
+	}
+
+	if len(analyses) > 0 {
+		report.AverageConfidence = totalConfidence / float64(len(analyses))
+	}
+
+	for _, count := range sectors {
+		report.Sectors = append(report.Sectors, *count)
+	}
+	sort.Slice(report.Sectors, func(i, j int) bool {
+		if report.Sectors[i].Count != report.Sectors[j].Count {
+			return report.Sectors[i].Count > report.Sectors[j].Count
+		}
+		return report.Sectors[i].Name < report.Sectors[j].Name
+	})
+
+	for _, count := range tickers {
+		report.Tickers = append(report.Tickers, *count)
+	}
+	sort.Slice(report.Tickers, func(i, j int) bool {
+		if report.Tickers[i].Count != report.Tickers[j].Count {
+			return report.Tickers[i].Count > report.Tickers[j].Count
+		}
+		return report.Tickers[i].Symbol < report.Tickers[j].Symbol
+	})
+
+	return report
+}
+
+// String renders the report as plain text
+func (r AggregateReport) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%d posts, average confidence %.0f%%\n", r.Posts, r.AverageConfidence*100)
+
+	sb.WriteString("Sectors:\n")
+	if len(r.Sectors) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, sector := range r.Sectors {
+		fmt.Fprintf(&sb, "  %s: %d (%d bullish, %d bearish)\n", sector.Name, sector.Count, sector.Bullish, sector.Bearish)
+	}
+
+	sb.WriteString("Tickers:\n")
+	if len(r.Tickers) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, ticker := range r.Tickers {
+		fmt.Fprintf(&sb, "  %s: %d\n", ticker.Symbol, ticker.Count)
+	}
+
+	return sb.String()
+}
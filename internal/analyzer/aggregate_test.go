@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	analyses := []*Analysis{
+		{MarketImpact: "bearish", Confidence: 0.9, AffectedSectors: []string{"Automotive", "Retail"}, SpecificStocks: []string{"GM", "$F"}},
+		{MarketImpact: "Bullish", Confidence: 0.6, AffectedSectors: []string{"Energy", "automotive"}, SpecificStocks: []string{"XOM", "gm"}},
+		{MarketImpact: "bearish", Confidence: 0.3, AffectedSectors: []string{"Retail", "Retail "}, SpecificStocks: []string{"WMT", "WMT"}},
+		{MarketImpact: "neutral", Confidence: 0.2},
+	}
+
+	report := Aggregate(analyses)
+
+	if report.Posts != 4 {
+		t.Errorf("Posts = %d, want 4", report.Posts)
+	}
+	if report.AverageConfidence < 0.4999 || report.AverageConfidence > 0.5001 {
+		t.Errorf("AverageConfidence = %f, want 0.5", report.AverageConfidence)
+	}
+
+	wantSectors := []SectorCount{
+		{Name: "Automotive", Count: 2, Bullish: 1, Bearish: 1},
+		{Name: "Retail", Count: 2, Bearish: 2},
+		{Name: "Energy", Count: 1, Bullish: 1},
+	}
+	if !reflect.DeepEqual(report.Sectors, wantSectors) {
+		t.Errorf("Sectors = %+v, want %+v", report.Sectors, wantSectors)
+	}
+
+	wantTickers := []TickerCount{
+		{Symbol: "GM", Count: 2},
+		{Symbol: "F", Count: 1},
+		{Symbol: "WMT", Count: 1},
+		{Symbol: "XOM", Count: 1},
+	}
+	if !reflect.DeepEqual(report.Tickers, wantTickers) {
+		t.Errorf("Tickers = %+v, want %+v", report.Tickers, wantTickers)
+	}
+}
+
+func TestAggregateReportString(t *testing.T) {
+	report := Aggregate([]*Analysis{
+		{MarketImpact: "bullish", Confidence: 0.8, AffectedSectors: []string{"Energy"}, SpecificStocks: []string{"XOM"}},
+	})
+
+	want := "1 posts, average confidence 80%\n" +
+		"Sectors:\n  Energy: 1 (1 bullish, 0 bearish)\n" +
+		"Tickers:\n  XOM: 1\n"
+	if got := report.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if got := Aggregate(nil).String(); got != "0 posts, average confidence 0%\nSectors:\n  none\nTickers:\n  none\n" {
+		t.Errorf("empty report = %q", got)
+	}
+}
@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the outcome of analyzing one post in a batch
+type BatchResult struct {
+	Analysis *Analysis
+	Err      error
+}
+
+// AnalyzeBatchConcurrent analyzes posts with at most workers completions in
+// flight. Results are in the same order as contents, with a per-post error
+// instead of dropping failures. Posts not yet started when ctx is cancelled
+// get ctx's error.
+func (ma *MarketAnalyzer) AnalyzeBatchConcurrent(ctx context.Context, contents []string, workers int) []BatchResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]BatchResult, len(contents))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, content := range contents {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(contents); j++ {
+				results[j].Err = ctx.Err()
+			}
+			wg.Wait()
+			return results
+		}
+
+		wg.Add(1)
+		go func(i int, content string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			analysis, _, err := ma.AnalyzePostRaw(ctx, content)
+			results[i] = BatchResult{Analysis: analysis, Err: err}
+		}(i, content)
+	}
+
+	wg.Wait()
+	return results
+}
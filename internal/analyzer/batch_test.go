@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+var postMarker = regexp.MustCompile(`post-\d+`)
+
+// slowCompleter echoes the post-N marker of each prompt back as the summary
+// after a delay, failing the posts listed in fail, and records the most
+// completions it saw in flight at once
+type slowCompleter struct {
+	delay time.Duration
+	fail  map[string]bool
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *slowCompleter) CreateChatCompletion(ctx context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+
+	s.mu.Lock()
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return openai.ChatCompletionResponse{}, ctx.Err()
+	}
+
+	marker := postMarker.FindString(request.Messages[len(request.Messages)-1].Content)
+	if s.fail[marker] {
+		return openai.ChatCompletionResponse{}, errors.New("server error")
+	}
+	reply := fmt.Sprintf(`{"market_impact": "neutral", "summary": %q}`, marker)
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: reply}}},
+	}, nil
+}
+
+func TestAnalyzeBatchConcurrentKeepsOrder(t *testing.T) {
+	fake := &slowCompleter{delay: 20 * time.Millisecond, fail: map[string]bool{"post-3": true}}
+	ma := NewMarketAnalyzerWithClient(fake)
+
+	var contents []string
+	for i := range 8 {
+		contents = append(contents, fmt.Sprintf("Tariffs are coming, post-%d", i))
+	}
+
+	results := ma.AnalyzeBatchConcurrent(context.Background(), contents, 3)
+
+	if len(results) != len(contents) {
+		t.Fatalf("got %d results, want %d", len(results), len(contents))
+	}
+	for i, result := range results {
+		want := fmt.Sprintf("post-%d", i)
+		if want == "post-3" {
+			if result.Err == nil {
+				t.Errorf("result %d: want the completion's error", i)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("result %d: %v", i, result.Err)
+			continue
+		}
+		if result.Analysis.Summary != want {
+			t.Errorf("result %d is the analysis of %s", i, result.Analysis.Summary)
+		}
+	}
+
+	if fake.maxInFlight > 3 {
+		t.Errorf("%d completions ran at once, want at most 3", fake.maxInFlight)
+	}
+	if fake.maxInFlight < 2 {
+		t.Errorf("completions never overlapped, the batch ran sequentially")
+	}
+}
+
+func TestAnalyzeBatchConcurrentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ma := NewMarketAnalyzerWithClient(&slowCompleter{})
+	results := ma.AnalyzeBatchConcurrent(ctx, []string{"post-1", "post-2", "post-3"}, 1)
+
+	for i, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("result %d: err = %v, want context.Canceled", i, result.Err)
+		}
+	}
+}
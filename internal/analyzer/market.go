@@ -3,9 +3,12 @@ package analyzer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"orangefeed/internal/features"
 	"orangefeed/internal/prompts"
 
 	"github.com/sashabaranov/go-openai"
@@ -23,64 +26,199 @@ type Analysis struct {
 	RiskLevel          string   `json:"risk_level"`          // "low", "medium", "high"
 	ExpectedMagnitude  string   `json:"expected_magnitude"`  // "minimal", "moderate", "significant", "major"
 	ActionableInsights []string `json:"actionable_insights"` // Specific trading recommendations
+
+	// Only requested with WithMoveEstimate: the estimated move of the first
+	// ticker in SpecificStocks, or of the S&P 500 when there is none
+	ExpectedMovePercent float64 `json:"expected_move_percent,omitempty"` // Size of the move, 0 when unknown
+	Direction           string  `json:"direction,omitempty"`             // "up", "down", "flat"
+
+	// How shouted the post is (caps and exclamation marks), 0-1; computed
+	// from the text rather than asked of the model
+	IntensityScore float64 `json:"intensity_score"`
+
+	// The model that produced the analysis, as reported by the API; empty
+	// when no model was asked
+	Model string `json:"model,omitempty"`
+
+	DroppedEntries int `json:"-"` // Duplicate sectors and invalid tickers removed by Normalize
+}
+
+// Matches reports whether the analysis touches any watchlist entry. Entries
+// are compared case-insensitively against both tickers and sectors, ignoring
+// a leading "$" on tickers.
+func (a *Analysis) Matches(watchlist []string) bool {
+	for _, entry := range watchlist {
+		entry = normalizeSymbol(entry)
+		if entry == "" {
+			continue
+		}
+
+		for _, stock := range a.SpecificStocks {
+			if normalizeSymbol(stock) == entry {
+				return true
+			}
+		}
+		for _, sector := range a.AffectedSectors {
+			if normalizeSymbol(sector) == entry {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func normalizeSymbol(s string) string {
+	return strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(s), "$"))
+}
+
+// defaultRequestTimeout bounds a single completion so one slow call can't
+// use up the budget of the posts after it
+const defaultRequestTimeout = 45 * time.Second
+
+// ErrTimeout is returned when a completion takes longer than the analyzer's
+// request timeout
+var ErrTimeout = errors.New("OpenAI request timed out")
+
+// completer is the part of the OpenAI client the analyzer uses.
+// *openai.Client satisfies it; tests can pass a fake.
+type completer interface {
+	CreateChatCompletion(ctx context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
 }
 
 type MarketAnalyzer struct {
-	openaiClient *openai.Client
+	openaiClient completer
+	apiKey       string
+	config       openai.ClientConfig
+	model        string
+
+	promptTemplate string        // Custom user prompt; the built-in one is used when empty
+	requestTimeout time.Duration // Per-completion timeout
+	sentimentOnly  bool          // Leave out trading signals, tickers and trade ideas
+	estimateMove   bool          // Ask for a numeric price move estimate
+	slots          chan struct{} // Bounds concurrent completions; nil means unlimited
+	limiter        *rateLimiter  // OpenAI requests and tokens per minute; nil means unlimited
+}
+
+func NewMarketAnalyzer(openaiKey string, opts ...Option) *MarketAnalyzer {
+	ma := &MarketAnalyzer{
+		apiKey: openaiKey,
+		config: openai.DefaultConfig(openaiKey),
+		model:  openai.GPT4,
+
+		requestTimeout: defaultRequestTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(ma)
+	}
+
+	ma.openaiClient = openai.NewClientWithConfig(ma.config)
+	return ma
 }
 
-func NewMarketAnalyzer(openaiKey string) *MarketAnalyzer {
-	return &MarketAnalyzer{
-		openaiClient: openai.NewClient(openaiKey),
+// NewMarketAnalyzerWithClient builds an analyzer around an existing client,
+// such as a fake in tests. Options that configure the connection (base URL,
+// Azure) have no effect.
+func NewMarketAnalyzerWithClient(client completer, opts ...Option) *MarketAnalyzer {
+	ma := &MarketAnalyzer{
+		model:          openai.GPT4,
+		requestTimeout: defaultRequestTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(ma)
 	}
+
+	ma.openaiClient = client
+	return ma
+}
+
+// AnalyzePost analyzes a single post. Optional notes give the model extra
+// context such as earlier posts.
+func (ma *MarketAnalyzer) AnalyzePost(content string, notes ...string) (*Analysis, error) {
+	analysis, _, err := ma.AnalyzePostRaw(context.Background(), content, notes...)
+	return analysis, err
 }
 
-func (ma *MarketAnalyzer) AnalyzePost(content string) (*Analysis, error) {
-	resp, err := ma.openaiClient.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT4,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: prompts.SystemPrompt(),
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompts.MarketAnalysisPrompt(content),
-				},
-			},
-			Temperature: 0.2, // Lower temperature for more consistent analysis
-			MaxTokens:   800, // Reduced for more concise responses
-		},
-	)
+// AnalyzePostRaw analyzes a single post and also returns the model's verbatim
+// response for auditing. The raw text is returned even when parsing fails.
+// The completion gets its own timeout within ctx; exceeding it returns
+// ErrTimeout. Completions rejected with 429 are retried with backoff.
+func (ma *MarketAnalyzer) AnalyzePostRaw(ctx context.Context, content string, notes ...string) (*Analysis, string, error) {
+	userPrompt := prompts.MarketAnalysisPrompt(content, notes...)
+	if ma.sentimentOnly {
+		userPrompt = prompts.SentimentAnalysisPrompt(content, notes...)
+	}
+	if ma.promptTemplate != "" {
+		userPrompt = prompts.TemplatePrompt(ma.promptTemplate, content, notes...)
+	}
+	if ma.estimateMove && !ma.sentimentOnly {
+		userPrompt += prompts.MoveEstimateInstructions()
+	}
+
+	// Wait for a free slot before the timeout starts so queued posts don't
+	// time out while waiting
+	if ma.slots != nil {
+		select {
+		case ma.slots <- struct{}{}:
+			defer func() { <-ma.slots }()
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+	}
 
+	resp, err := ma.complete(ctx, userPrompt)
 	if err != nil {
-		return nil, fmt.Errorf("OpenAI API error: %w", err)
+		return nil, "", err
 	}
 
 	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
+		return nil, "", fmt.Errorf("no response from OpenAI")
 	}
 
 	responseContent := resp.Choices[0].Message.Content
+	if strings.TrimSpace(responseContent) == "" {
+		// A refusal or content filter leaves the message empty
+		return nil, "", fmt.Errorf("empty response from OpenAI (finish reason %q)", resp.Choices[0].FinishReason)
+	}
 
-	// Try to extract JSON from the response
+	// Try to extract JSON from the response; models without JSON mode often
+	// wrap it in prose or code fences
 	jsonStart := strings.Index(responseContent, "{")
 	jsonEnd := strings.LastIndex(responseContent, "}") + 1
 
 	if jsonStart == -1 || jsonEnd == 0 {
-		return nil, fmt.Errorf("no JSON found in response: %s", responseContent)
+		return nil, responseContent, fmt.Errorf("no JSON found in response: %s", responseContent)
 	}
 
 	jsonContent := responseContent[jsonStart:jsonEnd]
 
 	var analysis Analysis
 	if err := json.Unmarshal([]byte(jsonContent), &analysis); err != nil {
-		return nil, fmt.Errorf("failed to parse analysis JSON: %w", err)
+		return nil, responseContent, fmt.Errorf("failed to parse analysis JSON: %w", err)
+	}
+	analysis.Normalize()
+	analysis.IntensityScore = features.Extract(content).Intensity
+
+	// The response names the model behind an Azure deployment or alias
+	analysis.Model = resp.Model
+	if analysis.Model == "" {
+		analysis.Model = ma.model
+	}
+
+	// Drop any advice the model volunteered anyway
+	if ma.sentimentOnly {
+		analysis.SpecificStocks = nil
+		analysis.TradingSignal = ""
+		analysis.ActionableInsights = nil
+	}
+	if ma.sentimentOnly || !ma.estimateMove {
+		analysis.ExpectedMovePercent = 0
+		analysis.Direction = ""
 	}
 
-	return &analysis, nil
+	return &analysis, responseContent, nil
 }
 
 // AnalyzeBatch analyzes multiple posts and returns aggregated insights
@@ -130,3 +268,23 @@ func (ma *MarketAnalyzer) GetMarketSentiment(analyses []*Analysis) string {
 
 	return "neutral"
 }
+
+// GetMarketSentimentScore scores the overall sentiment of recent analyses from
+// -1 (all bearish) to 1 (all bullish), weighting each by its confidence
+func (ma *MarketAnalyzer) GetMarketSentimentScore(analyses []*Analysis) float64 {
+	if len(analyses) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, analysis := range analyses {
+		switch strings.ToLower(analysis.MarketImpact) {
+		case "bullish":
+			total += analysis.Confidence
+		case "bearish":
+			total -= analysis.Confidence
+		}
+	}
+
+	return total / float64(len(analyses))
+}
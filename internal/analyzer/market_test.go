@@ -0,0 +1,205 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// fakeCompleter answers every completion with a fixed reply and keeps the
+// last request
+type fakeCompleter struct {
+	reply   string
+	model   string
+	request openai.ChatCompletionRequest
+}
+
+func (f *fakeCompleter) CreateChatCompletion(ctx context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	f.request = request
+	return openai.ChatCompletionResponse{
+		Model: f.model,
+		Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: f.reply}},
+		},
+	}, nil
+}
+
+const analysisJSON = `{
+  "market_impact": "bearish",
+  "confidence": 0.8,
+  "summary": "New tariffs on imports",
+  "key_points": ["25% tariff"],
+  "affected_sectors": ["Retail"],
+  "specific_stocks": ["WMT", "$TGT"],
+  "trading_signal": "sell"
+}`
+
+func TestAnalyzePostParsesJSON(t *testing.T) {
+	fake := &fakeCompleter{reply: analysisJSON, model: "gpt-4-0613"}
+	ma := NewMarketAnalyzerWithClient(fake)
+
+	analysis, raw, err := ma.AnalyzePostRaw(context.Background(), "Tariffs on everything!")
+	if err != nil {
+		t.Fatalf("AnalyzePostRaw: %v", err)
+	}
+	if raw != analysisJSON {
+		t.Errorf("raw = %q, want the model's reply", raw)
+	}
+	if analysis.MarketImpact != "bearish" || analysis.TradingSignal != "sell" {
+		t.Errorf("got %+v", analysis)
+	}
+	if got := strings.Join(analysis.SpecificStocks, ","); got != "WMT,TGT" {
+		t.Errorf("stocks = %s, want WMT,TGT", got)
+	}
+	if analysis.Model != "gpt-4-0613" {
+		t.Errorf("model = %q, want the one the response names", analysis.Model)
+	}
+	if fake.request.Model != openai.GPT4 {
+		t.Errorf("requested model %q, want %q", fake.request.Model, openai.GPT4)
+	}
+}
+
+func TestAnalyzePostExtractsFencedJSON(t *testing.T) {
+	reply := "Here is the analysis:\n```json\n" + analysisJSON + "\n```\nLet me know if you need more."
+	ma := NewMarketAnalyzerWithClient(&fakeCompleter{reply: reply})
+
+	analysis, raw, err := ma.AnalyzePostRaw(context.Background(), "Tariffs on everything!")
+	if err != nil {
+		t.Fatalf("AnalyzePostRaw: %v", err)
+	}
+	if raw != reply {
+		t.Errorf("raw = %q, want the whole reply", raw)
+	}
+	if analysis.MarketImpact != "bearish" || analysis.Confidence != 0.8 {
+		t.Errorf("got %+v", analysis)
+	}
+	if analysis.Model != openai.GPT4 {
+		t.Errorf("model = %q, want the requested one when the response names none", analysis.Model)
+	}
+}
+
+func TestAnalyzePostWithoutJSON(t *testing.T) {
+	reply := "I can't analyze this post."
+	ma := NewMarketAnalyzerWithClient(&fakeCompleter{reply: reply})
+
+	analysis, raw, err := ma.AnalyzePostRaw(context.Background(), "Tariffs on everything!")
+	if err == nil {
+		t.Fatalf("got %+v, want an error", analysis)
+	}
+	if !strings.Contains(err.Error(), "no JSON found") {
+		t.Errorf("error = %v, want one about missing JSON", err)
+	}
+	if raw != reply {
+		t.Errorf("raw = %q, want the reply for the audit log", raw)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	analysis := &Analysis{
+		SpecificStocks:  []string{"$AAPL", "tsla"},
+		AffectedSectors: []string{"Energy"},
+	}
+
+	tests := []struct {
+		watchlist []string
+		want      bool
+	}{
+		{[]string{"AAPL"}, true},
+		{[]string{"$aapl"}, true},
+		{[]string{" TSLA "}, true},
+		{[]string{"$TSLA"}, true},
+		{[]string{"energy"}, true},
+		{[]string{"MSFT", "Tech"}, false},
+		{[]string{"", "$"}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := analysis.Matches(tt.watchlist); got != tt.want {
+			t.Errorf("Matches(%q) = %t, want %t", tt.watchlist, got, tt.want)
+		}
+	}
+}
+
+// responseCompleter answers every request with resp
+type responseCompleter struct{ resp openai.ChatCompletionResponse }
+
+func (r responseCompleter) CreateChatCompletion(ctx context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	return r.resp, nil
+}
+
+func TestAnalyzePostEmptyResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		resp openai.ChatCompletionResponse
+		want string
+	}{
+		{"no choices", openai.ChatCompletionResponse{}, "no response"},
+		{"blank content", openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Content: " \n"}, FinishReason: openai.FinishReasonContentFilter},
+		}}, `empty response from OpenAI (finish reason "content_filter")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ma := NewMarketAnalyzerWithClient(responseCompleter{tt.resp})
+
+			analysis, err := ma.AnalyzePost("Tariffs on everything!")
+			if err == nil {
+				t.Fatalf("got %+v, want an error", analysis)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptOptionPrecedence(t *testing.T) {
+	const (
+		market    = "for market impact"
+		sentiment = "market sentiment"
+		template  = "Custom prompt for: Tariffs!"
+		estimate  = "expected_move_percent"
+	)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    []string
+		notWant []string
+	}{
+		{"default", nil, []string{market}, []string{sentiment, estimate}},
+		{"sentiment", []Option{WithSentimentOnly()}, []string{sentiment}, []string{market, estimate}},
+		{"estimate", []Option{WithMoveEstimate()}, []string{market, estimate}, []string{sentiment}},
+		{"sentiment and estimate", []Option{WithSentimentOnly(), WithMoveEstimate()}, []string{sentiment}, []string{estimate}},
+		{"template", []Option{WithPromptTemplate("Custom prompt for: %s")}, []string{template}, []string{market, sentiment, estimate}},
+		{"template and estimate", []Option{WithPromptTemplate("Custom prompt for: %s"), WithMoveEstimate()}, []string{template, estimate}, []string{market}},
+		{"template and sentiment", []Option{WithPromptTemplate("Custom prompt for: %s"), WithSentimentOnly()}, []string{template}, []string{sentiment}},
+		{"template, sentiment and estimate", []Option{WithPromptTemplate("Custom prompt for: %s"), WithSentimentOnly(), WithMoveEstimate()}, []string{template}, []string{sentiment, estimate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeCompleter{reply: analysisJSON}
+			ma := NewMarketAnalyzerWithClient(fake, tt.opts...)
+			if _, err := ma.AnalyzePost("Tariffs!"); err != nil {
+				t.Fatal(err)
+			}
+
+			prompt := fake.request.Messages[len(fake.request.Messages)-1].Content
+			for _, s := range tt.want {
+				if !strings.Contains(prompt, s) {
+					t.Errorf("prompt lacks %q:\n%s", s, prompt)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(prompt, s) {
+					t.Errorf("prompt has %q:\n%s", s, prompt)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxMovePercent is the largest move estimate taken at face value; anything
+// beyond it is treated as a hallucination
+const maxMovePercent = 25
+
+// tickerPattern matches a plain US ticker symbol
+var tickerPattern = regexp.MustCompile(`^[A-Z]{1,5}$`)
+
+// sectorAliases maps common short sector names to the name they duplicate
+var sectorAliases = map[string]string{
+	"tech":       "technology",
+	"pharma":     "pharmaceuticals",
+	"financials": "finance",
+	"financial":  "finance",
+	"autos":      "automotive",
+	"auto":       "automotive",
+}
+
+// Normalize coerces a model response into the documented shape. Small local
+// models tend to capitalize or invent enum values, give confidence as a
+// percentage and pad lists with empty strings; unknown enum values fall back
+// to the most neutral choice. Duplicate sectors and invalid tickers are
+// dropped and counted in DroppedEntries.
+func (a *Analysis) Normalize() {
+	a.MarketImpact = oneOf(a.MarketImpact, "neutral", "bullish", "bearish", "neutral")
+	a.TradingSignal = oneOf(a.TradingSignal, "watch", "buy", "sell", "hold", "watch")
+	a.TimeHorizon = oneOf(a.TimeHorizon, "short-term", "immediate", "short-term", "medium-term", "long-term")
+	a.RiskLevel = oneOf(a.RiskLevel, "medium", "low", "medium", "high")
+	a.ExpectedMagnitude = oneOf(a.ExpectedMagnitude, "minimal", "minimal", "moderate", "significant", "major")
+
+	// Accept percentages such as 85 as 0.85
+	if a.Confidence > 1 && a.Confidence <= 100 {
+		a.Confidence /= 100
+	}
+	a.Confidence = min(max(a.Confidence, 0), 1)
+
+	// The sign of the move may come as the direction or as a negative number
+	a.Direction = oneOf(a.Direction, "", "up", "down", "flat")
+	if a.Direction == "" && a.ExpectedMovePercent != 0 {
+		a.Direction = "up"
+		if a.ExpectedMovePercent < 0 {
+			a.Direction = "down"
+		}
+	}
+	a.ExpectedMovePercent = math.Abs(a.ExpectedMovePercent)
+	if math.IsNaN(a.ExpectedMovePercent) || a.ExpectedMovePercent > maxMovePercent {
+		a.ExpectedMovePercent = 0
+	}
+
+	a.Summary = strings.TrimSpace(a.Summary)
+	a.KeyPoints = compact(a.KeyPoints)
+	a.ActionableInsights = compact(a.ActionableInsights)
+
+	sectors := compact(a.AffectedSectors)
+	a.AffectedSectors = dedupeSectors(sectors)
+	a.DroppedEntries += len(sectors) - len(a.AffectedSectors)
+
+	stocks := compact(a.SpecificStocks)
+	a.SpecificStocks = validTickers(stocks)
+	a.DroppedEntries += len(stocks) - len(a.SpecificStocks)
+}
+
+// AddStocks appends tickers to SpecificStocks, normalized and skipping the
+// ones already listed
+func (a *Analysis) AddStocks(tickers ...string) {
+	a.SpecificStocks = validTickers(append(slices.Clone(a.SpecificStocks), tickers...))
+}
+
+// dedupeSectors keeps the first of sectors that only differ in case or are
+// known aliases of each other, like "Tech" and "Technology"
+func dedupeSectors(sectors []string) []string {
+	seen := make(map[string]bool, len(sectors))
+	var kept []string
+	for _, sector := range sectors {
+		key := strings.ToLower(sector)
+		if alias, ok := sectorAliases[key]; ok {
+			key = alias
+		}
+		if !seen[key] {
+			seen[key] = true
+			kept = append(kept, sector)
+		}
+	}
+	return kept
+}
+
+// validTickers upper-cases tickers, strips a leading "$" and drops anything
+// that isn't a plausible symbol, like "TRUMP2024", along with duplicates
+func validTickers(stocks []string) []string {
+	var kept []string
+	for _, stock := range stocks {
+		ticker := normalizeSymbol(stock)
+		if tickerPattern.MatchString(ticker) && !slices.Contains(kept, ticker) {
+			kept = append(kept, ticker)
+		}
+	}
+	return kept
+}
+
+// oneOf returns value lower-cased if it is one of allowed, otherwise def.
+// Values like "Short term" or "immediate (0-24h)" are matched by prefix.
+func oneOf(value, def string, allowed ...string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.ReplaceAll(value, " ", "-")
+	for _, option := range allowed {
+		if strings.HasPrefix(value, option) {
+			return option
+		}
+	}
+	return def
+}
+
+// compact trims entries and drops empty ones
+func compact(items []string) []string {
+	var kept []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeMessyOutput(t *testing.T) {
+	var a Analysis
+	err := json.Unmarshal([]byte(`{
+		"market_impact": "Bearish",
+		"confidence": 85,
+		"trading_signal": "SELL",
+		"time_horizon": "Short term (1-2 weeks)",
+		"risk_level": "very high",
+		"expected_magnitude": "",
+		"affected_sectors": ["Tech", "Technology", " Energy ", "", "energy", "Autos", "Automotive"],
+		"specific_stocks": ["$aapl", "AAPL", "TRUMP2024", "msft ", "", "BRK.B", "GOOGL"],
+		"key_points": [" Tariffs ", ""]
+	}`), &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Normalize()
+
+	if a.MarketImpact != "bearish" || a.TradingSignal != "sell" || a.TimeHorizon != "short-term" {
+		t.Errorf("enums = %q, %q, %q", a.MarketImpact, a.TradingSignal, a.TimeHorizon)
+	}
+	if a.RiskLevel != "medium" || a.ExpectedMagnitude != "minimal" {
+		t.Errorf("unknown enums fell back to %q and %q", a.RiskLevel, a.ExpectedMagnitude)
+	}
+	if a.Confidence != 0.85 {
+		t.Errorf("Confidence = %v, want 0.85", a.Confidence)
+	}
+	if want := []string{"Tech", "Energy", "Autos"}; !reflect.DeepEqual(a.AffectedSectors, want) {
+		t.Errorf("AffectedSectors = %q, want %q", a.AffectedSectors, want)
+	}
+	if want := []string{"AAPL", "MSFT", "GOOGL"}; !reflect.DeepEqual(a.SpecificStocks, want) {
+		t.Errorf("SpecificStocks = %q, want %q", a.SpecificStocks, want)
+	}
+	if want := []string{"Tariffs"}; !reflect.DeepEqual(a.KeyPoints, want) {
+		t.Errorf("KeyPoints = %q, want %q", a.KeyPoints, want)
+	}
+
+	// Technology, energy, Automotive, the second AAPL, TRUMP2024 and BRK.B;
+	// blank entries don't count
+	if a.DroppedEntries != 6 {
+		t.Errorf("DroppedEntries = %d, want 6", a.DroppedEntries)
+	}
+}
+
+func TestNormalizeMoveEstimate(t *testing.T) {
+	tests := []struct {
+		move          float64
+		direction     string
+		wantMove      float64
+		wantDirection string
+	}{
+		{-2.5, "", 2.5, "down"},
+		{1.5, "", 1.5, "up"},
+		{3, "Down", 3, "down"},
+		{80, "up", 0, "up"},
+		{0, "", 0, ""},
+	}
+
+	for _, tt := range tests {
+		a := Analysis{ExpectedMovePercent: tt.move, Direction: tt.direction}
+		a.Normalize()
+		if a.ExpectedMovePercent != tt.wantMove || a.Direction != tt.wantDirection {
+			t.Errorf("move %v %q normalized to %v %q, want %v %q",
+				tt.move, tt.direction, a.ExpectedMovePercent, a.Direction, tt.wantMove, tt.wantDirection)
+		}
+	}
+}
+
+func TestAddStocks(t *testing.T) {
+	a := Analysis{SpecificStocks: []string{"DJT"}}
+	a.AddStocks("$djt", "TSLA", "not a ticker")
+
+	if want := []string{"DJT", "TSLA"}; !reflect.DeepEqual(a.SpecificStocks, want) {
+		t.Errorf("SpecificStocks = %q, want %q", a.SpecificStocks, want)
+	}
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Option customizes a MarketAnalyzer
+type Option func(*MarketAnalyzer)
+
+// WithBaseURL points the analyzer at an OpenAI-compatible endpoint instead
+// of the public OpenAI API
+func WithBaseURL(baseURL string) Option {
+	return func(ma *MarketAnalyzer) {
+		ma.config.BaseURL = baseURL
+	}
+}
+
+// WithAzure sends requests to an Azure OpenAI resource. Azure addresses
+// models by deployment, so every request is routed to the given deployment.
+// An empty apiVersion keeps the library default.
+func WithAzure(endpoint, deployment, apiVersion string) Option {
+	return func(ma *MarketAnalyzer) {
+		ma.config = openai.DefaultAzureConfig(ma.apiKey, endpoint)
+		ma.config.AzureModelMapperFunc = func(string) string {
+			return deployment
+		}
+		if apiVersion != "" {
+			ma.config.APIVersion = apiVersion
+		}
+	}
+}
+
+// WithPromptTemplate replaces the built-in analysis prompt with a custom
+// template (see prompts.LoadTemplate). The template wins over the built-in
+// market and sentiment prompts alike; WithMoveEstimate still appends its
+// instructions to it, except with WithSentimentOnly, which also strips
+// trading advice from the result whatever the prompt asked for.
+func WithPromptTemplate(template string) Option {
+	return func(ma *MarketAnalyzer) {
+		ma.promptTemplate = template
+	}
+}
+
+// WithRequestTimeout sets how long a single completion may take
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(ma *MarketAnalyzer) {
+		ma.requestTimeout = timeout
+	}
+}
+
+// WithSentimentOnly restricts analyses to sentiment: no trading signal,
+// specific stocks or actionable insights are requested or returned
+func WithSentimentOnly() Option {
+	return func(ma *MarketAnalyzer) {
+		ma.sentimentOnly = true
+	}
+}
+
+// WithModel selects the chat model, e.g. "gpt-4o" or a local model name such
+// as "llama3.1:8b"
+func WithModel(model string) Option {
+	return func(ma *MarketAnalyzer) {
+		ma.model = model
+	}
+}
+
+// WithMaxConcurrency limits how many completions run at once across all
+// callers sharing the analyzer
+func WithMaxConcurrency(n int) Option {
+	return func(ma *MarketAnalyzer) {
+		if n > 0 {
+			ma.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithMoveEstimate asks the model for a numeric estimate of the price move
+// on top of the expected_magnitude category
+func WithMoveEstimate() Option {
+	return func(ma *MarketAnalyzer) {
+		ma.estimateMove = true
+	}
+}
+
+// WithRateLimit keeps completions within OpenAI's requests-per-minute and
+// tokens-per-minute limits, waiting for capacity before each call. A zero
+// limit leaves that dimension unlimited.
+func WithRateLimit(rpm, tpm int) Option {
+	return func(ma *MarketAnalyzer) {
+		if rpm > 0 || tpm > 0 {
+			ma.limiter = newRateLimiter(rpm, tpm)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"orangefeed/internal/prompts"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxCompletionTokens caps the model's answer, and is reserved up front
+// from the tokens-per-minute budget
+const maxCompletionTokens = 800
+
+// rateLimitRetries is how many times a completion rejected with 429 is
+// retried, waiting rateLimitBackoff and then twice as long each time
+const (
+	rateLimitRetries = 3
+	rateLimitBackoff = 5 * time.Second
+)
+
+// rateLimiter is a pair of token buckets for requests and tokens per minute.
+// A zero limit leaves that dimension unlimited.
+type rateLimiter struct {
+	mu       sync.Mutex
+	rpm, tpm float64
+	requests float64 // Requests available now
+	tokens   float64 // Tokens available now
+	last     time.Time
+	now      func() time.Time
+}
+
+func newRateLimiter(rpm, tpm int) *rateLimiter {
+	return &rateLimiter{
+		rpm:      float64(rpm),
+		tpm:      float64(tpm),
+		requests: float64(rpm),
+		tokens:   float64(tpm),
+		now:      time.Now,
+	}
+}
+
+// refill adds what accrued since the last call. The caller must hold mu.
+func (l *rateLimiter) refill() {
+	now := l.now()
+	if !l.last.IsZero() {
+		minutes := now.Sub(l.last).Minutes()
+		l.requests = min(l.requests+minutes*l.rpm, l.rpm)
+		l.tokens = min(l.tokens+minutes*l.tpm, l.tpm)
+	}
+	l.last = now
+}
+
+// wait blocks until one request and tokens are available and takes them.
+// A reservation larger than the whole budget waits for a full bucket.
+func (l *rateLimiter) wait(ctx context.Context, tokens int) error {
+	need := float64(tokens)
+	if l.tpm > 0 {
+		need = min(need, l.tpm)
+	}
+
+	for {
+		l.mu.Lock()
+		l.refill()
+
+		var delay time.Duration
+		if l.rpm > 0 && l.requests < 1 {
+			delay = max(delay, minutes((1-l.requests)/l.rpm))
+		}
+		if l.tpm > 0 && l.tokens < need {
+			delay = max(delay, minutes((need-l.tokens)/l.tpm))
+		}
+		if delay == 0 {
+			if l.rpm > 0 {
+				l.requests--
+			}
+			if l.tpm > 0 {
+				l.tokens -= need
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// settle returns the part of a reservation the call didn't use
+func (l *rateLimiter) settle(reserved, used int) {
+	if l.tpm == 0 || used <= 0 || used >= reserved {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.tokens+float64(reserved-used), l.tpm)
+}
+
+func minutes(m float64) time.Duration {
+	return time.Duration(m * float64(time.Minute))
+}
+
+// estimateTokens roughly counts the tokens a request may use: about four
+// characters per prompt token plus the largest possible answer
+func estimateTokens(prompt string) int {
+	return (len(prompts.SystemPrompt())+len(prompt))/4 + maxCompletionTokens
+}
+
+// isRateLimited reports whether OpenAI rejected a request with 429
+func isRateLimited(err error) bool {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode == http.StatusTooManyRequests
+	}
+	var reqErr *openai.RequestError
+	return errors.As(err, &reqErr) && reqErr.HTTPStatusCode == http.StatusTooManyRequests
+}
+
+// complete runs one chat completion within the rate limits, each attempt
+// under the analyzer's request timeout. Completions rejected with 429 are
+// retried with backoff.
+func (ma *MarketAnalyzer) complete(ctx context.Context, userPrompt string) (openai.ChatCompletionResponse, error) {
+	request := openai.ChatCompletionRequest{
+		Model: ma.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: prompts.SystemPrompt(),
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: userPrompt,
+			},
+		},
+		Temperature: 0.2,                 // Lower temperature for more consistent analysis
+		MaxTokens:   maxCompletionTokens, // Reduced for more concise responses
+	}
+
+	backoff := rateLimitBackoff
+	for attempt := 0; ; attempt++ {
+		reserved := estimateTokens(userPrompt)
+		if ma.limiter != nil {
+			if err := ma.limiter.wait(ctx, reserved); err != nil {
+				return openai.ChatCompletionResponse{}, err
+			}
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, ma.requestTimeout)
+		resp, err := ma.openaiClient.CreateChatCompletion(attemptCtx, request)
+		timedOut := errors.Is(attemptCtx.Err(), context.DeadlineExceeded)
+		cancel()
+
+		if err == nil {
+			if ma.limiter != nil {
+				ma.limiter.settle(reserved, resp.Usage.TotalTokens)
+			}
+			return resp, nil
+		}
+		// The caller's own deadline or cancellation isn't this request's timeout
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp, ctxErr
+		}
+		if timedOut {
+			return resp, fmt.Errorf("%w after %s", ErrTimeout, ma.requestTimeout)
+		}
+		if !isRateLimited(err) || attempt == rateLimitRetries {
+			return resp, fmt.Errorf("OpenAI API error: %w", err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, fmt.Errorf("OpenAI API error: %w", err)
+		}
+		backoff *= 2
+	}
+}
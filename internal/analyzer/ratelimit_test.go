@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// fakeClock is a manually advanced clock for the rate limiter
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestRateLimiterSerializesAtOneRPM(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)}
+	limiter := newRateLimiter(1, 0)
+	limiter.now = clock.Now
+
+	if err := limiter.wait(context.Background(), 100); err != nil {
+		t.Fatalf("first request waited: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx, 100); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second request in the same minute: err = %v, want it to block", err)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if err := limiter.wait(context.Background(), 100); err != nil {
+		t.Fatalf("request a minute later waited: %v", err)
+	}
+}
+
+func TestRateLimiterTokens(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)}
+	limiter := newRateLimiter(0, 1000)
+	limiter.now = clock.Now
+
+	if err := limiter.wait(context.Background(), 800); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only 200 tokens are left until the unused part is settled
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx, 500); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("over-budget request: err = %v, want it to block", err)
+	}
+
+	limiter.settle(800, 300)
+	if err := limiter.wait(context.Background(), 500); err != nil {
+		t.Fatalf("request after settling: %v", err)
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&openai.APIError{HTTPStatusCode: http.StatusTooManyRequests}, true},
+		{&openai.RequestError{HTTPStatusCode: http.StatusTooManyRequests}, true},
+		{&openai.APIError{HTTPStatusCode: http.StatusInternalServerError}, false},
+		{errors.New("connection reset"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isRateLimited(tt.err); got != tt.want {
+			t.Errorf("isRateLimited(%v) = %t, want %t", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteTimeoutOnlyForRequestDeadline(t *testing.T) {
+	ma := NewMarketAnalyzerWithClient(&slowCompleter{delay: time.Second}, WithRequestTimeout(10*time.Millisecond))
+	if _, _, err := ma.AnalyzePostRaw(context.Background(), "post-1"); !errors.Is(err, ErrTimeout) {
+		t.Errorf("request deadline: err = %v, want ErrTimeout", err)
+	}
+
+	// The caller's deadline runs out first
+	ma = NewMarketAnalyzerWithClient(&slowCompleter{delay: time.Second}, WithRequestTimeout(time.Minute))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := ma.AnalyzePostRaw(ctx, "post-1"); errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("caller deadline: err = %v, want the context's DeadlineExceeded", err)
+	}
+}
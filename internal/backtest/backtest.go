@@ -0,0 +1,130 @@
+// Package backtest compares an analysis' predicted market impact with the
+// price move that followed the post
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"orangefeed/internal/analyzer"
+	"orangefeed/internal/markethours"
+)
+
+// Bar is one trading day's closing price
+type Bar struct {
+	Date  time.Time // The trading day, at midnight UTC
+	Close float64
+}
+
+// PriceProvider returns daily closing prices of a ticker between two dates,
+// oldest first
+type PriceProvider interface {
+	DailyBars(ctx context.Context, ticker string, from, to time.Time) ([]Bar, error)
+}
+
+// flatMove is the largest change, as a fraction, still counted as neutral
+const flatMove = 0.005
+
+// horizonDays maps an analysis' time horizon to trading days after the post
+var horizonDays = map[string]int{
+	"immediate":   1,
+	"short-term":  5,
+	"medium-term": 21,
+	"long-term":   63,
+}
+
+// ErrNoPrices is returned when the provider has no closes around the post
+var ErrNoPrices = errors.New("no price data around the post")
+
+// Result is the realized move of a ticker over an analysis' time horizon
+type Result struct {
+	Ticker    string
+	Predicted string // The analysis' market_impact
+	Realized  string // "bullish", "bearish" or "neutral"
+	Change    float64
+	From, To  Bar
+	Days      int  // Trading days covered by the move
+	Complete  bool // Whether the whole time horizon has elapsed
+}
+
+// Hit reports whether the realized direction matches the prediction
+func (r Result) Hit() bool {
+	return r.Realized == r.Predicted
+}
+
+// Run measures ticker's move from the last close before postedAt over the
+// analysis' time horizon. Horizons that haven't elapsed yet report the move
+// so far.
+func Run(ctx context.Context, prices PriceProvider, ticker string, postedAt time.Time, analysis *analyzer.Analysis) (*Result, error) {
+	days, ok := horizonDays[strings.ToLower(analysis.TimeHorizon)]
+	if !ok {
+		days = horizonDays["short-term"]
+	}
+
+	// Calendar slack for weekends and holidays on both sides
+	from := postedAt.AddDate(0, 0, -10)
+	to := postedAt.AddDate(0, 0, days*7/5+10)
+	bars, err := prices.DailyBars(ctx, ticker, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	base := baseIndex(bars, postedAt)
+	if base < 0 || base == len(bars)-1 {
+		return nil, ErrNoPrices
+	}
+
+	end := min(base+days, len(bars)-1)
+	result := &Result{
+		Ticker:    strings.ToUpper(ticker),
+		Predicted: strings.ToLower(analysis.MarketImpact),
+		From:      bars[base],
+		To:        bars[end],
+		Days:      end - base,
+		Complete:  end-base == days,
+	}
+	result.Change = (result.To.Close - result.From.Close) / result.From.Close
+	result.Realized = direction(result.Change)
+
+	return result, nil
+}
+
+// baseIndex finds the last close the post could not have influenced, or -1
+func baseIndex(bars []Bar, postedAt time.Time) int {
+	local := markethours.InExchangeTime(postedAt)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	afterClose := local.Hour() >= 16
+
+	base := -1
+	for i, bar := range bars {
+		if bar.Date.Before(day) || (afterClose && bar.Date.Equal(day)) {
+			base = i
+		}
+	}
+	return base
+}
+
+func direction(change float64) string {
+	switch {
+	case change > flatMove:
+		return "bullish"
+	case change < -flatMove:
+		return "bearish"
+	default:
+		return "neutral"
+	}
+}
+
+// String summarizes the result, e.g. "AAPL +2.31% over 5 trading days"
+func (r Result) String() string {
+	s := fmt.Sprintf("%s %+.2f%% over %d trading days (%s → %s)",
+		r.Ticker, r.Change*100, r.Days,
+		r.From.Date.Format("2006-01-02"), r.To.Date.Format("2006-01-02"))
+	if !r.Complete {
+		s += ", horizon not over yet"
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package backtest
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultStooqURL serves daily OHLCV history as CSV without an API key
+const defaultStooqURL = "https://stooq.com/q/d/l/"
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// StooqProvider fetches daily closes from stooq.com. Tickers without an
+// exchange suffix are looked up on US exchanges.
+type StooqProvider struct {
+	BaseURL string // Defaults to stooq.com
+}
+
+func (s StooqProvider) DailyBars(ctx context.Context, ticker string, from, to time.Time) ([]Bar, error) {
+	base := s.BaseURL
+	if base == "" {
+		base = defaultStooqURL
+	}
+
+	symbol := strings.ToLower(ticker)
+	if !strings.Contains(symbol, ".") {
+		symbol += ".us"
+	}
+
+	query := url.Values{
+		"s":  {symbol},
+		"i":  {"d"},
+		"d1": {from.Format("20060102")},
+		"d2": {to.Format("20060102")},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("price request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, detail)
+	}
+
+	return parseStooqCSV(resp.Body, ticker)
+}
+
+// parseStooqCSV reads "Date,Open,High,Low,Close,Volume" rows. Unknown
+// symbols get a plain "No data" body instead of a header.
+func parseStooqCSV(r io.Reader, ticker string) ([]Bar, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prices: %w", err)
+	}
+	if len(rows) == 0 || len(rows[0]) < 5 || rows[0][0] != "Date" {
+		return nil, fmt.Errorf("no prices for %s", strings.ToUpper(ticker))
+	}
+
+	bars := make([]Bar, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		if len(row) < 5 {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", row[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", row[0], err)
+		}
+		closePrice, err := strconv.ParseFloat(row[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid close %q: %w", row[4], err)
+		}
+		bars = append(bars, Bar{Date: date, Close: closePrice})
+	}
+
+	return bars, nil
+}
@@ -0,0 +1,55 @@
+// Package features computes simple textual signals of a post, such as how
+// much of it is shouted
+package features
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// cashtagPattern matches "$TICKER" mentions
+var cashtagPattern = regexp.MustCompile(`\$[A-Za-z]{1,5}\b`)
+
+// Text holds the features of one post
+type Text struct {
+	CapsRatio    float64  // Share of letters that are uppercase, 0-1
+	Exclamations int      // Number of "!"
+	Cashtags     []string // Distinct tickers written as $TICKER, uppercased without "$"
+	Intensity    float64  // Combined shouting score, 0-1
+}
+
+// Extract computes the features of text
+func Extract(text string) Text {
+	var letters, upper int
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+
+	var t Text
+	if letters > 0 {
+		t.CapsRatio = float64(upper) / float64(letters)
+	}
+	t.Exclamations = strings.Count(text, "!")
+
+	seen := map[string]bool{}
+	for _, tag := range cashtagPattern.FindAllString(text, -1) {
+		tag = strings.ToUpper(strings.TrimPrefix(tag, "$"))
+		if !seen[tag] {
+			seen[tag] = true
+			t.Cashtags = append(t.Cashtags, tag)
+		}
+	}
+
+	// Ordinary sentence case is around 5% capitals; all caps and a string
+	// of exclamation marks both push the score up
+	caps := max(t.CapsRatio-0.1, 0) / 0.9
+	t.Intensity = min(0.7*caps+0.1*float64(min(t.Exclamations, 3)), 1)
+
+	return t
+}
@@ -0,0 +1,43 @@
+package features
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		caps         float64
+		exclamations int
+		cashtags     []string
+		intensity    float64
+	}{
+		{"shouting", "THE USA IS BACK!!!!", 1, 4, nil, 1},
+		{"sentence case", "Hello world.", 0.1, 0, nil, 0},
+		{"one exclamation", "hello world!", 0, 1, nil, 0.1},
+		{"cashtags", "Buy $aapl and $AAPL, sell $TSLA", 9.0 / 22, 0, []string{"AAPL", "TSLA"}, 0.7 * (9.0/22 - 0.1) / 0.9},
+		{"no letters", "!!! 100% ...", 0, 3, nil, 0.3},
+		{"empty", "", 0, 0, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Extract(tt.text)
+			if math.Abs(got.CapsRatio-tt.caps) > 1e-9 {
+				t.Errorf("CapsRatio = %v, want %v", got.CapsRatio, tt.caps)
+			}
+			if got.Exclamations != tt.exclamations {
+				t.Errorf("Exclamations = %d, want %d", got.Exclamations, tt.exclamations)
+			}
+			if !reflect.DeepEqual(got.Cashtags, tt.cashtags) {
+				t.Errorf("Cashtags = %q, want %q", got.Cashtags, tt.cashtags)
+			}
+			if math.Abs(got.Intensity-tt.intensity) > 1e-9 {
+				t.Errorf("Intensity = %v, want %v", got.Intensity, tt.intensity)
+			}
+		})
+	}
+}
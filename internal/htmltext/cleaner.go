@@ -0,0 +1,94 @@
+// Package htmltext turns post HTML into plain text for analysis and display
+package htmltext
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	anchorPattern    = regexp.MustCompile(`(?is)<a\s[^>]*?href\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+	breakPattern     = regexp.MustCompile(`(?i)<br\s*/?>`)
+	paragraphPattern = regexp.MustCompile(`(?i)</p>`)
+	tagPattern       = regexp.MustCompile(`<[^>]*>`)
+	spacePattern     = regexp.MustCompile(`[^\S\n]+`)
+	linePattern      = regexp.MustCompile(` ?\n ?`)
+	blankPattern     = regexp.MustCompile(`\n{3,}`)
+)
+
+// DefaultStripPatterns remove common repost and fundraising boilerplate:
+// "RT @user:" prefixes, "via @user" footers and donation appeals with links.
+// Patterns match the plain text line by line.
+var DefaultStripPatterns = []string{
+	`(?i)^\s*RT @\w+:\s*`,
+	`(?i)^\s*via:? @?\w[\w.]*\s*$`,
+	`(?i)^.*\b(donate|chip in)\b.*https?://\S+.*$`,
+	`(?i)^.*https?://\S*winred\.com\S*.*$`,
+}
+
+// Cleaner converts post HTML to plain text. Unlike plain tag stripping it
+// keeps the target of hyperlinks, so posts that are mostly a link still have
+// analyzable content, while hashtags, mentions and $TICKER cashtags stay
+// readable as written. Line breaks and paragraphs are kept, with at most one
+// blank line in a row. Text matching the strip patterns is removed.
+type Cleaner struct {
+	strip []*regexp.Regexp
+}
+
+// NewCleaner returns a cleaner using DefaultStripPatterns
+func NewCleaner() *Cleaner {
+	c, _ := NewCleanerWithPatterns(DefaultStripPatterns)
+	return c
+}
+
+// NewCleanerWithPatterns returns a cleaner removing text that matches any of
+// patterns, which are regular expressions applied to each line
+func NewCleanerWithPatterns(patterns []string) (*Cleaner, error) {
+	c := &Cleaner{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?m)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid strip pattern %q: %w", pattern, err)
+		}
+		c.strip = append(c.strip, re)
+	}
+	return c, nil
+}
+
+// Clean returns the plain text of an HTML post
+func (c *Cleaner) Clean(content string) string {
+	content = anchorPattern.ReplaceAllStringFunc(content, func(anchor string) string {
+		match := anchorPattern.FindStringSubmatch(anchor)
+		href, text := match[1], stripTags(match[2])
+
+		// Hashtags, mentions and cashtags read better as their text,
+		// while the visible text of a link is usually a truncated URL
+		if strings.HasPrefix(text, "#") || strings.HasPrefix(text, "@") || strings.HasPrefix(text, "$") {
+			return text
+		}
+		if href == "" {
+			return text
+		}
+		return " " + html.UnescapeString(href) + " "
+	})
+
+	content = breakPattern.ReplaceAllString(content, "\n")
+	content = paragraphPattern.ReplaceAllString(content, "\n\n")
+	content = html.UnescapeString(stripTags(content))
+
+	for _, re := range c.strip {
+		content = re.ReplaceAllString(content, "")
+	}
+
+	content = spacePattern.ReplaceAllString(content, " ")
+	content = linePattern.ReplaceAllString(content, "\n")
+	content = blankPattern.ReplaceAllString(content, "\n\n")
+
+	return strings.TrimSpace(content)
+}
+
+func stripTags(s string) string {
+	return strings.TrimSpace(tagPattern.ReplaceAllString(s, ""))
+}
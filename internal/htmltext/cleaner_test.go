@@ -0,0 +1,158 @@
+package htmltext
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name, html, want string
+	}{
+		{
+			name: "single hyperlink",
+			html: `<p><a href="https://www.foxnews.com/politics/tariffs-explained" rel="nofollow noopener" target="_blank"><span class="invisible">https://www.</span><span class="ellipsis">foxnews.com/politics/tariff</span></a></p>`,
+			want: "https://www.foxnews.com/politics/tariffs-explained",
+		},
+		{
+			name: "cashtags",
+			html: `<p>Buy <a href="https://truthsocial.com/tags/AAPL" class="mention hashtag">$AAPL</a> and <a href="https://truthsocial.com/tags/TSLA" class="mention hashtag">$<span>TSLA</span></a>, sell $DJT!</p>`,
+			want: "Buy $AAPL and $TSLA, sell $DJT!",
+		},
+		{
+			name: "hashtags and mentions",
+			html: `<p><a href="https://truthsocial.com/tags/MAGA">#MAGA</a> thanks <span class="h-card"><a href="https://truthsocial.com/@elonmusk">@<span>elonmusk</span></a></span></p>`,
+			want: "#MAGA thanks @elonmusk",
+		},
+		{
+			name: "escaped link",
+			html: `<p>Read <a href="https://example.com/?a=1&amp;b=2">example.com/?a=1</a> now</p>`,
+			want: "Read https://example.com/?a=1&b=2 now",
+		},
+		{
+			name: "paragraphs and breaks",
+			html: `<p>First line<br/>second line</p><p></p><p></p><p>Next &amp; last</p>`,
+			want: "First line\nsecond line\n\nNext & last",
+		},
+		{
+			name: "boilerplate",
+			html: `<p>RT @someone: Great news for farmers</p><p>Chip in now: https://secure.winred.com/give</p>`,
+			want: "Great news for farmers",
+		},
+	}
+
+	c := NewCleaner()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Clean(tt.html); got != tt.want {
+				t.Errorf("Clean() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCleanerWithPatternsRejectsInvalid(t *testing.T) {
+	if _, err := NewCleanerWithPatterns([]string{"("}); err == nil {
+		t.Error("want an error for an invalid pattern")
+	}
+}
+
+func TestCleanKeepsParagraphs(t *testing.T) {
+	tests := []struct {
+		name, html, want string
+	}{
+		{
+			name: "long statement",
+			html: `<p>The Tariffs on China are now at 145%.</p><p>They have been ripping us off for years.</p><p>Those days are OVER!</p>`,
+			want: "The Tariffs on China are now at 145%.\n\nThey have been ripping us off for years.\n\nThose days are OVER!",
+		},
+		{
+			name: "signature after breaks",
+			html: `<p>Thank you!<br><br><br>President DJT</p>`,
+			want: "Thank you!\n\nPresident DJT",
+		},
+		{
+			name: "spaces around breaks",
+			html: "<p>One  <br />   two\t<BR>three </P>\n<p> four</p>",
+			want: "One\ntwo\nthree\n\nfour",
+		},
+		{
+			name: "single paragraph",
+			html: `<p>Just one line</p>`,
+			want: "Just one line",
+		},
+	}
+
+	c := NewCleaner()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Clean(tt.html); got != tt.want {
+				t.Errorf("Clean() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanStripsBoilerplate(t *testing.T) {
+	tests := []struct {
+		name, html, want string
+	}{
+		{
+			name: "repost prefix",
+			html: `<p>RT <span class="h-card"><a href="https://truthsocial.com/@DanScavino">@<span>DanScavino</span></a></span>: Record crowds in Michigan today!</p>`,
+			want: "Record crowds in Michigan today!",
+		},
+		{
+			name: "via footer",
+			html: `<p>The Fake News won't report this.</p><p>via @BreitbartNews</p>`,
+			want: "The Fake News won't report this.",
+		},
+		{
+			name: "donation appeal",
+			html: `<p>They are coming after me again!</p><p>Please DONATE before midnight: <a href="https://secure.example.org/donate">secure.example.org/donate</a></p>`,
+			want: "They are coming after me again!",
+		},
+		{
+			name: "winred link",
+			html: `<p>MAGA!</p><p><a href="https://secure.winred.com/trump-national-committee-jfc/lp-website">secure.winred.com/trump-nati</a></p>`,
+			want: "MAGA!",
+		},
+		{
+			name: "keeps ordinary mentions of donations",
+			html: `<p>Big donors are leaving the Democrats.</p>`,
+			want: "Big donors are leaving the Democrats.",
+		},
+		{
+			name: "only boilerplate",
+			html: `<p>Chip in $5: https://secure.winred.com/give</p>`,
+			want: "",
+		},
+	}
+
+	c := NewCleaner()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Clean(tt.html); got != tt.want {
+				t.Errorf("Clean() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanerWithCustomPatterns(t *testing.T) {
+	c, err := NewCleanerWithPatterns(append(slices.Clone(DefaultStripPatterns), `(?i)^\s*President DJT\s*$`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := c.Clean(`<p>RT @someone: Tariffs are working!</p><p>President DJT</p>`)
+	if want := "Tariffs are working!"; got != want {
+		t.Errorf("Clean() = %q, want %q", got, want)
+	}
+
+	// Without patterns nothing is stripped
+	c, _ = NewCleanerWithPatterns(nil)
+	if got := c.Clean(`<p>RT @someone: Tariffs are working!</p>`); got != "RT @someone: Tariffs are working!" {
+		t.Errorf("Clean() without patterns = %q", got)
+	}
+}
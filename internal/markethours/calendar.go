@@ -0,0 +1,159 @@
+// Package markethours knows when the US stock market is open for regular
+// trading
+package markethours
+
+import (
+	"time"
+	_ "time/tzdata" // The exchange calendar needs America/New_York on any host
+)
+
+// newYork is the exchange's time zone
+var newYork = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Regular session, in minutes after midnight New York time
+const (
+	openMinute  = 9*60 + 30
+	closeMinute = 16 * 60
+)
+
+// IsOpen reports whether t falls within the regular NYSE/Nasdaq session:
+// 9:30 to 16:00 New York time on a weekday that isn't a market holiday.
+// Early closes are treated as full days.
+func IsOpen(t time.Time) bool {
+	t = t.In(newYork)
+	if !IsTradingDay(t) {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	return minute >= openMinute && minute < closeMinute
+}
+
+// IsTradingDay reports whether the New York calendar day of t is a weekday
+// that isn't a market holiday
+func IsTradingDay(t time.Time) bool {
+	t = t.In(newYork)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return !isHoliday(t.Year(), t.Month(), t.Day())
+}
+
+// InExchangeTime converts t to New York time
+func InExchangeTime(t time.Time) time.Time {
+	return t.In(newYork)
+}
+
+// NextOpen returns the start of the next regular session after t, or t
+// itself when the market is open
+func NextOpen(t time.Time) time.Time {
+	if IsOpen(t) {
+		return t
+	}
+
+	local := t.In(newYork)
+	day := time.Date(local.Year(), local.Month(), local.Day(), openMinute/60, openMinute%60, 0, 0, newYork)
+	if !local.Before(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	for !IsTradingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day
+}
+
+// isHoliday reports whether the date is a full-day NYSE holiday
+func isHoliday(year int, month time.Month, day int) bool {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	for _, holiday := range holidays(year) {
+		if holiday.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// holidays returns the observed NYSE holidays for a year
+func holidays(year int) []time.Time {
+	easter := easterSunday(year)
+	return []time.Time{
+		newYearsDay(year),
+		nthWeekday(year, time.January, time.Monday, 3),    // Martin Luther King Jr. Day
+		nthWeekday(year, time.February, time.Monday, 3),   // Washington's Birthday
+		easter.AddDate(0, 0, -2),                          // Good Friday
+		lastWeekday(year, time.May, time.Monday),          // Memorial Day
+		observed(date(year, time.June, 19)),               // Juneteenth
+		observed(date(year, time.July, 4)),                // Independence Day
+		nthWeekday(year, time.September, time.Monday, 1),  // Labor Day
+		nthWeekday(year, time.November, time.Thursday, 4), // Thanksgiving
+		observed(date(year, time.December, 25)),           // Christmas
+	}
+}
+
+// newYearsDay is only moved forward: when January 1 is a Saturday the
+// market stays open on the last trading day of the previous year
+func newYearsDay(year int) time.Time {
+	d := date(year, time.January, 1)
+	if d.Weekday() == time.Sunday {
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// observed moves a holiday falling on a weekend to the nearest weekday
+func observed(d time.Time) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	default:
+		return d
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday returns the nth (1-based) given weekday of the month
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	d := date(year, month, 1)
+	offset := (int(weekday) - int(d.Weekday()) + 7) % 7
+	return d.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last given weekday of the month
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	d := date(year, month+1, 1).AddDate(0, 0, -1)
+	offset := (int(d.Weekday()) - int(weekday) + 7) % 7
+	return d.AddDate(0, 0, -offset)
+}
+
+// easterSunday computes Western Easter with the anonymous Gregorian algorithm
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"strings"
+)
+
+// discordMaxContent is Discord's limit on a webhook message's content
+const discordMaxContent = 2000
+
+// discordSuppressNotifications is the message flag for silent messages
+const discordSuppressNotifications = 1 << 12
+
+// DiscordNotifier posts alerts to a Discord channel through a webhook
+type DiscordNotifier struct {
+	WebhookURL string
+}
+
+type discordPayload struct {
+	Content string `json:"content"`
+	Flags   int    `json:"flags,omitempty"`
+}
+
+func (d DiscordNotifier) Notify(ctx context.Context, msg Message) error {
+	// Discord reads **bold**; a single * would render as italics
+	content := strings.ReplaceAll(msg.Text, "*", "**")
+	content = strings.ReplaceAll(content, `\**`, `\*`)
+	if runes := []rune(content); len(runes) > discordMaxContent {
+		content = string(runes[:discordMaxContent-1]) + "…"
+	}
+
+	payload := discordPayload{Content: content}
+	if msg.Silent {
+		payload.Flags = discordSuppressNotifications
+	}
+
+	return postJSON(ctx, d.WebhookURL, payload)
+}
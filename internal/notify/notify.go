@@ -0,0 +1,68 @@
+// Package notify delivers alerts to chat services and webhooks
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"orangefeed/internal/analyzer"
+)
+
+// Message is an alert about one post
+type Message struct {
+	PostID   string             `json:"post_id"`
+	PostURL  string             `json:"post_url"`
+	Content  string             `json:"content"` // The post's cleaned text
+	Text     string             `json:"text"`    // The formatted alert, in Telegram Markdown
+	Analysis *analyzer.Analysis `json:"analysis"` // Nil when the post is forwarded without analysis
+	Silent   bool               `json:"silent,omitempty"` // Low priority; deliver without a notification where supported
+}
+
+// Notifier sends alerts to one destination
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// httpClient is shared by the webhook-based notifiers
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// postJSON sends v as a JSON POST body and treats any non-2xx response as
+// an error
+func postJSON(ctx context.Context, url string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
+
+// HTTPNotifier POSTs every Message as JSON to a URL
+type HTTPNotifier struct {
+	URL string
+}
+
+func (h HTTPNotifier) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, h.URL, msg)
+}
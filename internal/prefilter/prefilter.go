@@ -0,0 +1,109 @@
+// Package prefilter scores posts for market relevance with keywords, so the
+// model is only asked about posts that could move markets
+package prefilter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"orangefeed/internal/features"
+)
+
+// Term is a keyword or phrase and how much it adds to a post's score. A
+// trailing "*" matches any word ending, e.g. "tariff*" matches "tariffs".
+type Term struct {
+	Pattern string
+	Weight  float64
+}
+
+// DefaultTerms covers trade, monetary policy, the economy and companies
+// that are often named in market-moving posts
+var DefaultTerms = []Term{
+	{"tariff*", 3}, {"trade war", 3}, {"trade deal", 2}, {"imports", 1}, {"exports", 1}, {"sanction*", 2},
+	{"fed", 3}, {"federal reserve", 3}, {"powell", 3}, {"interest rates", 3}, {"rates", 2}, {"rate cut", 3},
+	{"inflation", 3}, {"recession", 3}, {"economy", 2}, {"gdp", 2}, {"jobs", 1}, {"unemployment", 2},
+	{"stock market", 3}, {"stocks", 2}, {"dow", 2}, {"nasdaq", 2}, {"s&p", 2},
+	{"treasury", 2}, {"bonds", 1}, {"dollar", 1}, {"deficit", 1}, {"debt ceiling", 2}, {"tax", 2}, {"taxes", 2},
+	{"china", 2}, {"chinese", 2}, {"mexico", 1}, {"canada", 1}, {"european union", 1},
+	{"oil", 2}, {"opec", 2}, {"gas prices", 2}, {"steel", 2}, {"aluminum", 2}, {"semiconductor*", 2},
+	{"crypto*", 2}, {"bitcoin", 2},
+	{"apple", 2}, {"tesla", 2}, {"nvidia", 2}, {"amazon", 2}, {"boeing", 2}, {"intel", 2}, {"microsoft", 2}, {"google", 2},
+}
+
+// cashtagWeight is added for every distinct "$TICKER" in a post
+const cashtagWeight = 2
+
+type compiledTerm struct {
+	re     *regexp.Regexp
+	weight float64
+}
+
+// Filter scores posts against a list of terms
+type Filter struct {
+	terms     []compiledTerm
+	threshold float64
+}
+
+// New builds a filter that passes posts scoring at least threshold
+func New(terms []Term, threshold float64) (*Filter, error) {
+	f := &Filter{threshold: threshold}
+	for _, term := range terms {
+		pattern := strings.ToLower(strings.TrimSpace(term.Pattern))
+		prefix := strings.HasSuffix(pattern, "*")
+		pattern = strings.TrimSuffix(pattern, "*")
+		if pattern == "" {
+			return nil, fmt.Errorf("empty prefilter term")
+		}
+
+		expr := `(?i)(^|\W)` + regexp.QuoteMeta(pattern)
+		if prefix {
+			expr += `\w*`
+		}
+		expr += `(\W|$)`
+
+		f.terms = append(f.terms, compiledTerm{re: regexp.MustCompile(expr), weight: term.Weight})
+	}
+	return f, nil
+}
+
+// ParseTerms reads "term" or "term=weight" entries; weights default to 1
+func ParseTerms(entries []string) ([]Term, error) {
+	terms := make([]Term, 0, len(entries))
+	for _, entry := range entries {
+		pattern, weight, found := strings.Cut(entry, "=")
+		term := Term{Pattern: strings.TrimSpace(pattern), Weight: 1}
+		if found {
+			w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight in prefilter term %q: %w", entry, err)
+			}
+			term.Weight = w
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// Score adds up the weights of the terms found in text, each counted once,
+// plus a fixed weight per distinct cashtag
+func (f *Filter) Score(text string) float64 {
+	var score float64
+	for _, term := range f.terms {
+		if term.re.MatchString(text) {
+			score += term.weight
+		}
+	}
+
+	score += cashtagWeight * float64(len(features.Extract(text).Cashtags))
+
+	return score
+}
+
+// Relevant reports whether text scores at least the threshold, along with
+// the score
+func (f *Filter) Relevant(text string) (bool, float64) {
+	score := f.Score(text)
+	return score >= f.threshold, score
+}
@@ -1,13 +1,17 @@
 package prompts
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
-// MarketAnalysisPrompt generates a concise but effective prompt for market analysis
-func MarketAnalysisPrompt(content string) string {
+// MarketAnalysisPrompt generates a concise but effective prompt for market analysis.
+// Optional notes are listed as background context after the post.
+func MarketAnalysisPrompt(content string, notes ...string) string {
 	return fmt.Sprintf(`Analyze this Trump post for market impact. Respond with ONLY valid JSON:
 
 Post: "%s"
-
+%s
 Required JSON format:
 {
   "summary": "1 concise sentence (max 80 chars)",
@@ -28,10 +32,104 @@ Focus on:
 - Policy implications (trade, regulation, rates)
 - Specific actionable trades
 
-Be extremely concise. Chat format requires brevity.`, content)
+Be extremely concise. Chat format requires brevity.`, content, contextSection(notes))
+}
+
+// SentimentAnalysisPrompt is a variant of MarketAnalysisPrompt that asks only
+// for sentiment, leaving out trading signals, tickers and trade ideas
+func SentimentAnalysisPrompt(content string, notes ...string) string {
+	return fmt.Sprintf(`Analyze the market sentiment of this Trump post. Respond with ONLY valid JSON:
+
+Post: "%s"
+%s
+Required JSON format:
+{
+  "summary": "1 concise sentence (max 80 chars)",
+  "market_impact": "bullish/bearish/neutral",
+  "confidence": 0.0-1.0,
+  "affected_sectors": ["max 2 sectors"]
+}
+
+Describe the likely market mood only. Do not recommend trades or name specific stocks.
+
+Be extremely concise. Chat format requires brevity.`, content, contextSection(notes))
+}
+
+// MoveEstimateInstructions extends the requested JSON with a numeric price
+// move estimate
+func MoveEstimateInstructions() string {
+	return `
+
+Also include these fields in the JSON:
+  "expected_move_percent": estimated size of the price move in percent (e.g. 1.5) for the first ticker in specific_stocks, or for the S&P 500 if there is none,
+  "direction": "up/down/flat"`
+}
+
+// contextSection renders background notes as a bulleted block
+func contextSection(notes []string) string {
+	if len(notes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\nContext:\n")
+	for _, note := range notes {
+		sb.WriteString("- " + note + "\n")
+	}
+	return sb.String()
 }
 
 // SystemPrompt returns the system prompt for the AI analyst
 func SystemPrompt() string {
 	return "You are a senior quantitative analyst. Provide ultra-concise market analysis for chat format. Keep all responses brief and actionable. Focus on immediate impact and specific trades."
 }
+
+// AuthorTickersNote tells the model the post is about a company tied to its
+// author, whose stock is directly affected
+func AuthorTickersNote(username string, tickers []string) string {
+	return fmt.Sprintf("The post mentions a company tied to @%s; consider its impact on %s", username, strings.Join(tickers, ", "))
+}
+
+// EngagementNote describes how quickly the post is gaining likes and reblogs
+func EngagementNote(likes, reblogs int, period string) string {
+	return fmt.Sprintf("The post gained %d likes and %d reblogs in the last %s", likes, reblogs, period)
+}
+
+// OffHoursNote tells the model the post was made while the US market was
+// closed, so any reaction waits for the next open
+func OffHoursNote(nextOpen string) string {
+	return fmt.Sprintf("The post was made outside US market hours; the market reopens %s, so any reaction is delayed until then", nextOpen)
+}
+
+// IntensityNote points out a shouted post, which tends to signal the author
+// means business
+func IntensityNote(capsPercent, exclamations int) string {
+	return fmt.Sprintf("The post is written with intensity: %d%% capital letters and %d exclamation marks", capsPercent, exclamations)
+}
+
+// priorPostLength caps each earlier post quoted in PriorPostsNote
+const priorPostLength = 300
+
+// PriorPostsNote gives the author's earlier posts, newest first, so the
+// model can follow references like "as I said yesterday"
+func PriorPostsNote(posts []string) string {
+	var sb strings.Builder
+	sb.WriteString("The author's previous posts, newest first:")
+	for i, post := range posts {
+		if runes := []rune(post); len(runes) > priorPostLength {
+			post = string(runes[:priorPostLength]) + "…"
+		}
+		sb.WriteString(fmt.Sprintf(" (%d) %q", i+1, post))
+	}
+	return sb.String()
+}
+
+// AuthorNote describes who wrote the post, since the same words carry more
+// weight from a head of state than from a pundit
+func AuthorNote(username, followers string, verified bool) string {
+	note := fmt.Sprintf("The author is @%s with %s followers", username, followers)
+	if verified {
+		note += ", verified"
+	}
+	return note
+}
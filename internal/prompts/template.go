@@ -0,0 +1,42 @@
+package prompts
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadTemplate reads a custom analysis prompt from a file. The template must
+// contain exactly one %s placeholder, which receives the post content.
+func LoadTemplate(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt template: %w", err)
+	}
+
+	template := string(data)
+	if err := ValidateTemplate(template); err != nil {
+		return "", fmt.Errorf("invalid prompt template %s: %w", filename, err)
+	}
+
+	return template, nil
+}
+
+// ValidateTemplate checks that a template has exactly one %s placeholder and
+// no other formatting verbs (a literal percent sign must be written as %%)
+func ValidateTemplate(template string) error {
+	verbs := strings.Count(strings.ReplaceAll(template, "%%", ""), "%")
+	placeholders := strings.Count(strings.ReplaceAll(template, "%%", ""), "%s")
+
+	if placeholders != 1 || verbs != 1 {
+		return fmt.Errorf("expected exactly one %%s placeholder and no other %% verbs, found %d placeholders", placeholders)
+	}
+
+	return nil
+}
+
+// TemplatePrompt fills a custom template with the post content, followed by
+// any background notes
+func TemplatePrompt(template, content string, notes ...string) string {
+	return fmt.Sprintf(template, content) + contextSection(notes)
+}
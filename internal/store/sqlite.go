@@ -0,0 +1,243 @@
+// Package store persists processed posts and their analyses in SQLite
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"orangefeed/internal/analyzer"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS analyses (
+	post_id             TEXT PRIMARY KEY,
+	username            TEXT NOT NULL,
+	content             TEXT NOT NULL,
+	url                 TEXT NOT NULL,
+	created_at          TEXT NOT NULL,
+	status_json         TEXT NOT NULL,
+	summary             TEXT NOT NULL,
+	market_impact       TEXT NOT NULL,
+	confidence          REAL NOT NULL,
+	trading_signal      TEXT NOT NULL,
+	time_horizon        TEXT NOT NULL,
+	risk_level          TEXT NOT NULL,
+	expected_magnitude  TEXT NOT NULL,
+	key_points          TEXT NOT NULL,
+	affected_sectors    TEXT NOT NULL,
+	specific_stocks     TEXT NOT NULL,
+	actionable_insights TEXT NOT NULL,
+	analysis_json       TEXT NOT NULL,
+	raw_response        TEXT NOT NULL DEFAULT '',
+	processed_at        TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS analyses_username ON analyses (username);
+CREATE INDEX IF NOT EXISTS analyses_processed_at ON analyses (processed_at);
+
+CREATE TABLE IF NOT EXISTS state (
+	key   TEXT PRIMARY KEY,
+	value BLOB NOT NULL
+);`
+
+// Record is a processed post together with its analysis
+type Record struct {
+	PostID      string
+	Username    string
+	Content     string
+	URL         string
+	CreatedAt   string
+	Status      json.RawMessage // The post as returned by the API
+	Analysis    *analyzer.Analysis
+	RawResponse string // The model's verbatim output
+	ProcessedAt time.Time
+}
+
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the SQLite database at path
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows a single writer; serialize access instead of hitting SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveAnalysis stores a processed post and its analysis, replacing any
+// earlier record for the same post
+func (s *Store) SaveAnalysis(ctx context.Context, rec Record) error {
+	if rec.Analysis == nil {
+		return fmt.Errorf("record for post %s has no analysis", rec.PostID)
+	}
+	if rec.ProcessedAt.IsZero() {
+		rec.ProcessedAt = time.Now()
+	}
+	if rec.Status == nil {
+		rec.Status = json.RawMessage("null")
+	}
+
+	a := rec.Analysis
+	lists := make([]string, 0, 4)
+	for _, list := range [][]string{a.KeyPoints, a.AffectedSectors, a.SpecificStocks, a.ActionableInsights} {
+		encoded, err := encodeList(list)
+		if err != nil {
+			return err
+		}
+		lists = append(lists, encoded)
+	}
+
+	analysisJSON, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO analyses (
+			post_id, username, content, url, created_at, status_json,
+			summary, market_impact, confidence, trading_signal, time_horizon,
+			risk_level, expected_magnitude, key_points, affected_sectors,
+			specific_stocks, actionable_insights, analysis_json, raw_response, processed_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.PostID, rec.Username, rec.Content, rec.URL, rec.CreatedAt, string(rec.Status),
+		a.Summary, a.MarketImpact, a.Confidence, a.TradingSignal, a.TimeHorizon,
+		a.RiskLevel, a.ExpectedMagnitude, lists[0], lists[1],
+		lists[2], lists[3], string(analysisJSON), rec.RawResponse, rec.ProcessedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save analysis for post %s: %w", rec.PostID, err)
+	}
+
+	return nil
+}
+
+// LastPostID returns the newest stored post ID for username, or "" if none.
+// IDs are numeric strings, so longer IDs are newer.
+func (s *Store) LastPostID(ctx context.Context, username string) (string, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT post_id FROM analyses WHERE username = ?
+		ORDER BY length(post_id) DESC, post_id DESC LIMIT 1`, username).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query last post ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// recordColumns are the columns scanRecord reads, in order
+const recordColumns = `post_id, username, content, url, created_at, status_json, analysis_json, raw_response, processed_at`
+
+// RecentAnalyses returns the n most recently processed records, newest first
+func (s *Store) RecentAnalyses(ctx context.Context, n int) ([]Record, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+recordColumns+`
+		FROM analyses ORDER BY processed_at DESC LIMIT ?`, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		rec, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *rec)
+	}
+
+	return records, rows.Err()
+}
+
+// Analysis returns the stored record for a post, or nil if there is none
+func (s *Store) Analysis(ctx context.Context, postID string) (*Record, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+recordColumns+` FROM analyses WHERE post_id = ?`, postID)
+	rec, err := scanRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return rec, err
+}
+
+// scanRecord reads one row of recordColumns
+func scanRecord(row interface{ Scan(...any) error }) (*Record, error) {
+	var rec Record
+	var status, analysisJSON string
+	err := row.Scan(&rec.PostID, &rec.Username, &rec.Content, &rec.URL, &rec.CreatedAt,
+		&status, &analysisJSON, &rec.RawResponse, &rec.ProcessedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis: %w", err)
+	}
+
+	rec.Status = json.RawMessage(status)
+	rec.Analysis = &analyzer.Analysis{}
+	if err := json.Unmarshal([]byte(analysisJSON), rec.Analysis); err != nil {
+		return nil, fmt.Errorf("failed to decode analysis for post %s: %w", rec.PostID, err)
+	}
+
+	return &rec, nil
+}
+
+// LoadState returns the value stored under key, or nil if there is none
+func (s *Store) LoadState(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM state WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// SaveState stores value under key
+func (s *Store) SaveState(ctx context.Context, key string, value []byte) error {
+	_, err := s.db.ExecContext(ctx, `INSERT OR REPLACE INTO state (key, value) VALUES (?, ?)`, key, value)
+	if err != nil {
+		return fmt.Errorf("failed to save state %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func encodeList(items []string) (string, error) {
+	if items == nil {
+		items = []string{}
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode list: %w", err)
+	}
+
+	return string(data), nil
+}
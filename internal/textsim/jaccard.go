@@ -0,0 +1,39 @@
+// Package textsim measures how similar two pieces of text are
+package textsim
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokens returns the set of lower-cased words in text, ignoring punctuation
+func Tokens(text string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '$'
+	})
+
+	tokens := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		tokens[word] = struct{}{}
+	}
+	return tokens
+}
+
+// Jaccard returns the Jaccard similarity of the word sets of a and b, from 0
+// (no words in common) to 1 (same words). A text without words, like an
+// image-only post, is similar to nothing.
+func Jaccard(a, b string) float64 {
+	tokensA, tokensB := Tokens(a), Tokens(b)
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for token := range tokensA {
+		if _, ok := tokensB[token]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(tokensA)+len(tokensB)-shared)
+}
@@ -0,0 +1,36 @@
+package textsim
+
+import "testing"
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"identical", "Tariffs on China", "tariffs on china!", 1},
+		{"disjoint", "tariffs on china", "great rally today", 0},
+		{"half shared", "buy $AAPL now", "sell $AAPL now", 0.5},
+		{"both empty", "", "", 0},
+		{"punctuation only", "!!!", "...", 0},
+		{"one empty", "", "tariffs on china", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Jaccard(tt.a, tt.b); got != tt.want {
+				t.Errorf("Jaccard(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokensKeepCashtags(t *testing.T) {
+	tokens := Tokens("Buy $TSLA, not $tsla!")
+	if len(tokens) != 3 {
+		t.Fatalf("Tokens = %v, want 3 distinct words", tokens)
+	}
+	if _, ok := tokens["$tsla"]; !ok {
+		t.Errorf("Tokens = %v, want $tsla", tokens)
+	}
+}